@@ -0,0 +1,171 @@
+package groq
+
+import (
+	"errors"
+	"io"
+	"sort"
+
+	"github.com/conneroisu/groq-go/pkg/tools"
+)
+
+type (
+	// ChatCompletionAccumulator rebuilds a ChatCompletionResponse from the
+	// chunks of a chat completion stream.
+	//
+	// The zero value is ready to use.
+	ChatCompletionAccumulator struct {
+		response ChatCompletionResponse
+		choices  map[int]*accumulatedChoice
+	}
+	// accumulatedChoice is a choice being rebuilt from stream deltas.
+	accumulatedChoice struct {
+		choice    ChatCompletionChoice
+		toolCalls map[int]*tools.ToolCall
+	}
+)
+
+// Add merges a chunk of a chat completion stream into the accumulated
+// response.
+func (a *ChatCompletionAccumulator) Add(chunk *ChatCompletionStreamResponse) {
+	if chunk == nil {
+		return
+	}
+	if a.choices == nil {
+		a.choices = make(map[int]*accumulatedChoice)
+	}
+	if chunk.ID != "" {
+		a.response.ID = chunk.ID
+	}
+	if chunk.Created != 0 {
+		a.response.Created = chunk.Created
+	}
+	if chunk.Model != "" {
+		a.response.Model = chunk.Model
+	}
+	if chunk.SystemFingerprint != "" {
+		a.response.SystemFingerprint = chunk.SystemFingerprint
+	}
+	if chunk.Usage != nil {
+		a.response.Usage = *chunk.Usage
+	}
+	if chunk.XGroq != nil && chunk.XGroq.Usage != nil {
+		a.response.Usage = *chunk.XGroq.Usage
+	}
+	for _, streamChoice := range chunk.Choices {
+		a.addChoice(streamChoice)
+	}
+}
+
+// addChoice merges the delta of a stream choice into its accumulated
+// choice.
+func (a *ChatCompletionAccumulator) addChoice(streamChoice ChatCompletionStreamChoice) {
+	acc, ok := a.choices[streamChoice.Index]
+	if !ok {
+		acc = &accumulatedChoice{
+			choice: ChatCompletionChoice{
+				Index:   streamChoice.Index,
+				Message: ChatCompletionMessage{Role: RoleAssistant},
+			},
+			toolCalls: make(map[int]*tools.ToolCall),
+		}
+		a.choices[streamChoice.Index] = acc
+	}
+	delta := streamChoice.Delta
+	message := &acc.choice.Message
+	if delta.Role != "" {
+		message.Role = Role(delta.Role)
+	}
+	message.Content += delta.Content
+	if delta.FunctionCall != nil {
+		if message.FunctionCall == nil {
+			message.FunctionCall = &tools.FunctionCall{}
+		}
+		if delta.FunctionCall.Name != "" {
+			message.FunctionCall.Name = delta.FunctionCall.Name
+		}
+		message.FunctionCall.Arguments += delta.FunctionCall.Arguments
+	}
+	for pos, call := range delta.ToolCalls {
+		index := pos
+		if call.Index != nil {
+			index = *call.Index
+		}
+		merged, ok := acc.toolCalls[index]
+		if !ok {
+			merged = &tools.ToolCall{}
+			acc.toolCalls[index] = merged
+		}
+		if call.ID != "" {
+			merged.ID = call.ID
+		}
+		if call.Type != "" {
+			merged.Type = call.Type
+		}
+		if call.Function.Name != "" {
+			merged.Function.Name = call.Function.Name
+		}
+		merged.Function.Arguments += call.Function.Arguments
+	}
+	if streamChoice.FinishReason != "" &&
+		streamChoice.FinishReason != ReasonNull {
+		acc.choice.FinishReason = streamChoice.FinishReason
+	}
+	if streamChoice.LogProbs != nil {
+		if acc.choice.LogProbs == nil {
+			acc.choice.LogProbs = &LogProbs{}
+		}
+		acc.choice.LogProbs.Content = append(
+			acc.choice.LogProbs.Content,
+			streamChoice.LogProbs.Content...,
+		)
+	}
+}
+
+// Response returns the response accumulated so far.
+func (a *ChatCompletionAccumulator) Response() ChatCompletionResponse {
+	response := a.response
+	response.Object = "chat.completion"
+	response.Choices = make([]ChatCompletionChoice, 0, len(a.choices))
+	for _, acc := range a.choices {
+		choice := acc.choice
+		choice.Message.ToolCalls = nil
+		indices := make([]int, 0, len(acc.toolCalls))
+		for index := range acc.toolCalls {
+			indices = append(indices, index)
+		}
+		sort.Ints(indices)
+		for _, index := range indices {
+			choice.Message.ToolCalls = append(
+				choice.Message.ToolCalls,
+				*acc.toolCalls[index],
+			)
+		}
+		response.Choices = append(response.Choices, choice)
+	}
+	sort.Slice(response.Choices, func(i, j int) bool {
+		return response.Choices[i].Index < response.Choices[j].Index
+	})
+	return response
+}
+
+// Accumulate reads the remaining chunks of the stream and rebuilds them
+// into a single ChatCompletionResponse.
+//
+// The stream is closed once it has been read.
+func (s *ChatCompletionStream) Accumulate() (ChatCompletionResponse, error) {
+	defer s.Close()
+	var acc ChatCompletionAccumulator
+	for {
+		chunk, err := s.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return acc.Response(), err
+		}
+		acc.Add(chunk)
+	}
+	response := acc.Response()
+	response.header = s.Header
+	return response, nil
+}
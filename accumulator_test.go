@@ -0,0 +1,95 @@
+package groq_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// streamChunks are the chunks of a streamed response calling two tools in
+// parallel followed by the usage chunk.
+var streamChunks = []string{
+	`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"llama3-8b-8192","choices":[{"index":0,"delta":{"role":"assistant","content":"Let me "},"finish_reason":null}]}`,
+	`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"llama3-8b-8192","choices":[{"index":0,"delta":{"content":"check.","tool_calls":[{"index":0,"id":"call_a","type":"function","function":{"name":"weather","arguments":"{\"ci"}}]},"finish_reason":null,"logprobs":{"content":[{"token":"Let","logprob":-0.1,"top_logprobs":[]}]}}]}`,
+	`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"llama3-8b-8192","choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"id":"call_b","type":"function","function":{"name":"time","arguments":"{}"}}]},"finish_reason":null}]}`,
+	`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"llama3-8b-8192","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"ty\":\"Paris\"}"}}]},"finish_reason":null,"logprobs":{"content":[{"token":" me","logprob":-0.2,"top_logprobs":[]}]}}]}`,
+	`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"llama3-8b-8192","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}],"x_groq":{"id":"req_1","usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}}`,
+}
+
+// TestChatCompletionStreamAccumulate tests that a stream is rebuilt into a
+// full response.
+func TestChatCompletionStreamAccumulate(t *testing.T) {
+	a := assert.New(t)
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			var b strings.Builder
+			for _, chunk := range streamChunks {
+				b.WriteString("data: " + chunk + "\n\n")
+			}
+			b.WriteString("data: [DONE]\n\n")
+			_, err := w.Write([]byte(b.String()))
+			a.NoError(err, "Write error")
+		},
+	)
+	stream, err := client.ChatCompletionStream(
+		context.Background(),
+		groq.ChatCompletionRequest{
+			Model: groq.ModelLlama38B8192,
+			Messages: []groq.ChatCompletionMessage{
+				{Role: groq.RoleUser, Content: "Weather and time in Paris?"},
+			},
+		},
+	)
+	a.NoError(err)
+	resp, err := stream.Accumulate()
+	a.NoError(err)
+	a.Equal("chatcmpl-1", resp.ID)
+	a.Equal(groq.ModelLlama38B8192, resp.Model)
+	a.Equal(15, resp.Usage.TotalTokens)
+	a.Len(resp.Choices, 1)
+	choice := resp.Choices[0]
+	a.Equal(groq.ReasonToolCalls, choice.FinishReason)
+	a.Equal(groq.RoleAssistant, choice.Message.Role)
+	a.Equal("Let me check.", choice.Message.Content)
+	a.Len(choice.Message.ToolCalls, 2)
+	a.Equal("call_a", choice.Message.ToolCalls[0].ID)
+	a.Equal("weather", choice.Message.ToolCalls[0].Function.Name)
+	a.Equal(`{"city":"Paris"}`, choice.Message.ToolCalls[0].Function.Arguments)
+	a.Equal("call_b", choice.Message.ToolCalls[1].ID)
+	a.Equal("{}", choice.Message.ToolCalls[1].Function.Arguments)
+	a.NotNil(choice.LogProbs)
+	a.Len(choice.LogProbs.Content, 2)
+}
+
+// TestChatCompletionAccumulatorChoices tests that multiple choices are
+// accumulated separately and ordered by index.
+func TestChatCompletionAccumulatorChoices(t *testing.T) {
+	a := assert.New(t)
+	var acc groq.ChatCompletionAccumulator
+	acc.Add(&groq.ChatCompletionStreamResponse{
+		Choices: []groq.ChatCompletionStreamChoice{
+			{Index: 1, Delta: groq.ChatCompletionStreamChoiceDelta{Content: "b"}},
+			{Index: 0, Delta: groq.ChatCompletionStreamChoiceDelta{Content: "a"}},
+		},
+	})
+	acc.Add(&groq.ChatCompletionStreamResponse{
+		Choices: []groq.ChatCompletionStreamChoice{
+			{Index: 0, Delta: groq.ChatCompletionStreamChoiceDelta{Content: "c"}, FinishReason: groq.ReasonStop},
+		},
+		Usage: &groq.Usage{TotalTokens: 3},
+	})
+	resp := acc.Response()
+	a.Len(resp.Choices, 2)
+	a.Equal("ac", resp.Choices[0].Message.Content)
+	a.Equal(groq.ReasonStop, resp.Choices[0].FinishReason)
+	a.Equal("b", resp.Choices[1].Message.Content)
+	a.Equal(3, resp.Usage.TotalTokens)
+}
@@ -0,0 +1,329 @@
+package groq
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"github.com/conneroisu/groq-go/pkg/tools"
+)
+
+const (
+	// defaultMaxIterations is the default maximum number of chat
+	// completions an agent makes in a single run.
+	defaultMaxIterations = 10
+)
+
+type (
+	// Agent runs the tool calling loop of a chat completion.
+	//
+	// It calls the model, dispatches the tool calls of its reply to the
+	// registered handlers and providers, appends their results to the
+	// conversation and calls the model again until it stops calling
+	// tools.
+	Agent struct {
+		client        *Client
+		handlers      map[string]toolHandler
+		providers     []ToolProvider
+		maxIterations int
+		tokenBudget   int
+		beforeCall    BeforeToolCallHook
+		afterCall     AfterToolCallHook
+	}
+	// AgentOpts is a function that sets options for an Agent.
+	AgentOpts func(*Agent)
+	// ToolHandler is a Go function handling calls of a tool.
+	//
+	// The returned string is sent back to the model as the tool's result.
+	ToolHandler func(ctx context.Context, call tools.ToolCall) (string, error)
+	// ToolProvider provides tools and runs calls of them.
+	//
+	// The toolhouse, e2b and composio extensions implement it.
+	ToolProvider interface {
+		// Tools returns the tools of the provider.
+		Tools(ctx context.Context) ([]tools.Tool, error)
+		// RunTool runs a single call of one of the provider's tools.
+		RunTool(
+			ctx context.Context,
+			call tools.ToolCall,
+		) (ChatCompletionMessage, error)
+	}
+	// BeforeToolCallHook is called before a tool call is run.
+	//
+	// Returning an error skips the call and reports the error to the
+	// model as the tool's result.
+	BeforeToolCallHook func(ctx context.Context, call tools.ToolCall) error
+	// AfterToolCallHook is called after a tool call has run with its
+	// result and error.
+	AfterToolCallHook func(
+		ctx context.Context,
+		call tools.ToolCall,
+		result ChatCompletionMessage,
+		err error,
+	)
+	// AgentResult is the result of an agent run.
+	AgentResult struct {
+		// Response is the last chat completion of the run.
+		Response ChatCompletionResponse
+		// Messages is the conversation including the messages added
+		// during the run.
+		Messages []ChatCompletionMessage
+		// Usage is the total usage of every chat completion of the run.
+		Usage Usage
+		// Iterations is the number of chat completions made.
+		Iterations int
+	}
+	// toolHandler is a tool registered with its Go handler.
+	toolHandler struct {
+		tool    tools.Tool
+		handler ToolHandler
+	}
+	// toolRunner runs the calls of a single tool.
+	toolRunner func(
+		ctx context.Context,
+		call tools.ToolCall,
+	) (ChatCompletionMessage, error)
+)
+
+// NewAgent creates a new agent running tools for the given client.
+func NewAgent(client *Client, opts ...AgentOpts) *Agent {
+	a := &Agent{
+		client:        client,
+		handlers:      make(map[string]toolHandler),
+		maxIterations: defaultMaxIterations,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// WithToolHandler registers a Go handler for the given tool.
+//
+// Handlers are keyed by the name of the function of their tool, so a later
+// handler for a tool of the same name replaces the earlier one.
+func WithToolHandler(tool tools.Tool, handler ToolHandler) AgentOpts {
+	return func(a *Agent) {
+		a.handlers[tool.Function.Name] = toolHandler{
+			tool:    tool,
+			handler: handler,
+		}
+	}
+}
+
+// WithToolProvider adds a tool provider to the agent.
+func WithToolProvider(provider ToolProvider) AgentOpts {
+	return func(a *Agent) { a.providers = append(a.providers, provider) }
+}
+
+// WithMaxIterations sets the maximum number of chat completions of a run.
+func WithMaxIterations(n int) AgentOpts {
+	return func(a *Agent) { a.maxIterations = n }
+}
+
+// WithTokenBudget sets the maximum number of total tokens a run may use.
+//
+// A run stops once the budget is spent. Zero disables the budget.
+func WithTokenBudget(tokens int) AgentOpts {
+	return func(a *Agent) { a.tokenBudget = tokens }
+}
+
+// WithBeforeToolCall sets the hook called before each tool call.
+func WithBeforeToolCall(hook BeforeToolCallHook) AgentOpts {
+	return func(a *Agent) { a.beforeCall = hook }
+}
+
+// WithAfterToolCall sets the hook called after each tool call.
+func WithAfterToolCall(hook AfterToolCallHook) AgentOpts {
+	return func(a *Agent) { a.afterCall = hook }
+}
+
+// Run runs the tool calling loop for the request until the model replies
+// without calling a tool.
+//
+// The tools of the registered handlers and providers are added to the
+// request if it does not specify any. Tool calls of a reply are run
+// concurrently unless the request disables parallel tool calls.
+func (a *Agent) Run(
+	ctx context.Context,
+	request ChatCompletionRequest,
+) (result AgentResult, err error) {
+	runners, available, err := a.collectTools(ctx)
+	if err != nil {
+		return result, err
+	}
+	if len(request.Tools) == 0 {
+		request.Tools = available
+	}
+	result.Messages = append(
+		make([]ChatCompletionMessage, 0, len(request.Messages)),
+		request.Messages...,
+	)
+	for result.Iterations < a.maxIterations {
+		if a.tokenBudget > 0 && result.Usage.TotalTokens >= a.tokenBudget {
+			return result, groqerr.ErrAgentTokenBudget{
+				Budget: a.tokenBudget,
+				Used:   result.Usage.TotalTokens,
+			}
+		}
+		request.Messages = result.Messages
+		result.Response, err = a.client.ChatCompletion(ctx, request)
+		if err != nil {
+			return result, err
+		}
+		result.Iterations++
+		result.Usage.PromptTokens += result.Response.Usage.PromptTokens
+		result.Usage.CompletionTokens += result.Response.Usage.CompletionTokens
+		result.Usage.TotalTokens += result.Response.Usage.TotalTokens
+		if len(result.Response.Choices) == 0 {
+			return result, fmt.Errorf("chat completion has no choices")
+		}
+		message := result.Response.Choices[0].Message
+		result.Messages = append(result.Messages, message)
+		if len(message.ToolCalls) == 0 {
+			return result, nil
+		}
+		results := a.runToolCalls(
+			ctx,
+			runners,
+			message.ToolCalls,
+			parallelToolCalls(request),
+		)
+		result.Messages = append(result.Messages, results...)
+		if err = ctx.Err(); err != nil {
+			return result, err
+		}
+	}
+	return result, groqerr.ErrAgentMaxIterations{Iterations: a.maxIterations}
+}
+
+// collectTools returns the runners of every tool keyed by name along with
+// the tool definitions.
+func (a *Agent) collectTools(
+	ctx context.Context,
+) (map[string]toolRunner, []tools.Tool, error) {
+	runners := make(map[string]toolRunner)
+	var available []tools.Tool
+	for name, registered := range a.handlers {
+		runners[name] = handlerRunner(registered.handler)
+		available = append(available, registered.tool)
+	}
+	sort.Slice(available, func(i, j int) bool {
+		return available[i].Function.Name < available[j].Function.Name
+	})
+	for _, provider := range a.providers {
+		provided, err := provider.Tools(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, tool := range provided {
+			runners[tool.Function.Name] = provider.RunTool
+			available = append(available, tool)
+		}
+	}
+	return runners, available, nil
+}
+
+// runToolCalls runs the tool calls and returns their results in the order
+// of the calls.
+func (a *Agent) runToolCalls(
+	ctx context.Context,
+	runners map[string]toolRunner,
+	calls []tools.ToolCall,
+	parallel bool,
+) []ChatCompletionMessage {
+	results := make([]ChatCompletionMessage, len(calls))
+	if !parallel || len(calls) == 1 {
+		for i, call := range calls {
+			results[i] = a.runToolCall(ctx, runners, call)
+		}
+		return results
+	}
+	var wg sync.WaitGroup
+	for i, call := range calls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = a.runToolCall(ctx, runners, call)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// runToolCall runs a single tool call and returns its result as a tool
+// message.
+//
+// The call is passed through the middleware of the client as an
+// "ExecuteTool" operation. Errors are reported to the model as the content
+// of the message so that it can recover from them.
+func (a *Agent) runToolCall(
+	ctx context.Context,
+	runners map[string]toolRunner,
+	call tools.ToolCall,
+) ChatCompletionMessage {
+	var (
+		result ChatCompletionMessage
+		err    error
+	)
+	if a.beforeCall != nil {
+		err = a.beforeCall(ctx, call)
+	}
+	if err == nil {
+		runner, ok := runners[call.Function.Name]
+		if !ok {
+			err = groqerr.ErrToolNotFound{ToolName: call.Function.Name}
+		} else {
+			result, err = invoke(
+				ctx,
+				a.client,
+				"ExecuteTool",
+				"",
+				call,
+				runner,
+			)
+		}
+	}
+	if err != nil {
+		result.Content = fmt.Sprintf(
+			"Error running tool %s: %s",
+			call.Function.Name,
+			err.Error(),
+		)
+	}
+	result.Role = RoleTool
+	result.ToolCallID = call.ID
+	if result.Name == "" {
+		result.Name = call.Function.Name
+	}
+	if a.afterCall != nil {
+		a.afterCall(ctx, call, result, err)
+	}
+	return result
+}
+
+// handlerRunner adapts a ToolHandler to a toolRunner.
+func handlerRunner(handler ToolHandler) toolRunner {
+	return func(
+		ctx context.Context,
+		call tools.ToolCall,
+	) (ChatCompletionMessage, error) {
+		content, err := handler(ctx, call)
+		return ChatCompletionMessage{Content: content}, err
+	}
+}
+
+// parallelToolCalls reports whether the request allows running tool calls
+// in parallel.
+func parallelToolCalls(request ChatCompletionRequest) bool {
+	switch v := request.ParallelToolCalls.(type) {
+	case bool:
+		return v
+	case *bool:
+		return v == nil || *v
+	default:
+		return true
+	}
+}
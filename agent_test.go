@@ -0,0 +1,297 @@
+package groq_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"github.com/conneroisu/groq-go/pkg/tools"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	weatherTool = tools.Tool{
+		Type: tools.ToolTypeFunction,
+		Function: tools.FunctionDefinition{
+			Name:        "weather",
+			Description: "Get the weather of a city.",
+			Parameters: tools.FunctionParameters{
+				Type: "object",
+				Properties: map[string]tools.PropertyDefinition{
+					"city": {Type: "string", Description: "The city."},
+				},
+				Required: []string{"city"},
+			},
+		},
+	}
+	timeTool = tools.Tool{
+		Type: tools.ToolTypeFunction,
+		Function: tools.FunctionDefinition{
+			Name:        "time",
+			Description: "Get the current time.",
+		},
+	}
+)
+
+// toolCallsResponse returns a chat completion calling the given tools.
+func toolCallsResponse(calls ...tools.ToolCall) groq.ChatCompletionResponse {
+	return groq.ChatCompletionResponse{
+		ID: "chatcmpl-tools",
+		Choices: []groq.ChatCompletionChoice{{
+			Message: groq.ChatCompletionMessage{
+				Role:      groq.RoleAssistant,
+				ToolCalls: calls,
+			},
+			FinishReason: groq.ReasonToolCalls,
+		}},
+		Usage: groq.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+	}
+}
+
+// setupAgentTestServer creates a client whose chat completion endpoint
+// replies with the given responses in order and records the requests.
+func setupAgentTestServer(
+	t *testing.T,
+	responses ...groq.ChatCompletionResponse,
+) (*groq.Client, *[]groq.ChatCompletionRequest, func()) {
+	t.Helper()
+	client, server, teardown := setupGroqTestServer()
+	var (
+		mu       sync.Mutex
+		requests []groq.ChatCompletionRequest
+	)
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			var req groq.ChatCompletionRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			requests = append(requests, req)
+			response := responses[min(len(requests), len(responses))-1]
+			_ = json.NewEncoder(w).Encode(response)
+		},
+	)
+	return client, &requests, teardown
+}
+
+// TestAgentRun tests that the agent runs tool calls until the model stops.
+func TestAgentRun(t *testing.T) {
+	a := assert.New(t)
+	client, requests, teardown := setupAgentTestServer(t,
+		toolCallsResponse(
+			tools.ToolCall{ID: "call_1", Type: "function", Function: tools.FunctionCall{Name: "weather", Arguments: `{"city":"Paris"}`}},
+			tools.ToolCall{ID: "call_2", Type: "function", Function: tools.FunctionCall{Name: "time", Arguments: `{}`}},
+		),
+		groq.ChatCompletionResponse{
+			Choices: []groq.ChatCompletionChoice{{
+				Message: groq.ChatCompletionMessage{
+					Role:    groq.RoleAssistant,
+					Content: "It is sunny in Paris at noon.",
+				},
+				FinishReason: groq.ReasonStop,
+			}},
+			Usage: groq.Usage{TotalTokens: 20},
+		},
+	)
+	defer teardown()
+	var before, after atomic.Int32
+	agent := groq.NewAgent(
+		client,
+		groq.WithToolHandler(weatherTool, func(_ context.Context, call tools.ToolCall) (string, error) {
+			var args struct {
+				City string `json:"city"`
+			}
+			if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
+				return "", err
+			}
+			return "sunny in " + args.City, nil
+		}),
+		groq.WithToolHandler(timeTool, func(context.Context, tools.ToolCall) (string, error) {
+			return "noon", nil
+		}),
+		groq.WithBeforeToolCall(func(context.Context, tools.ToolCall) error {
+			before.Add(1)
+			return nil
+		}),
+		groq.WithAfterToolCall(func(_ context.Context, _ tools.ToolCall, _ groq.ChatCompletionMessage, err error) {
+			a.NoError(err)
+			after.Add(1)
+		}),
+	)
+	result, err := agent.Run(context.Background(), groq.ChatCompletionRequest{
+		Model: groq.ModelLlama3Groq70B8192ToolUsePreview,
+		Messages: []groq.ChatCompletionMessage{
+			{Role: groq.RoleUser, Content: "Weather and time in Paris?"},
+		},
+	})
+	a.NoError(err)
+	a.Equal(2, result.Iterations)
+	a.Equal(35, result.Usage.TotalTokens)
+	a.EqualValues(2, before.Load())
+	a.EqualValues(2, after.Load())
+	a.Equal("It is sunny in Paris at noon.", result.Response.Choices[0].Message.Content)
+	a.Len(result.Messages, 5)
+	a.Equal(groq.RoleTool, result.Messages[2].Role)
+	a.Equal("call_1", result.Messages[2].ToolCallID)
+	a.Equal("sunny in Paris", result.Messages[2].Content)
+	a.Equal("call_2", result.Messages[3].ToolCallID)
+	a.Equal("noon", result.Messages[3].Content)
+	a.Len(*requests, 2)
+	a.Len((*requests)[0].Tools, 2)
+	a.Len((*requests)[1].Messages, 4)
+}
+
+// TestAgentDuplicateToolHandlers tests that a handler for a tool of an
+// already registered name replaces the earlier one.
+func TestAgentDuplicateToolHandlers(t *testing.T) {
+	a := assert.New(t)
+	client, requests, teardown := setupAgentTestServer(t,
+		toolCallsResponse(
+			tools.ToolCall{ID: "call_1", Type: "function", Function: tools.FunctionCall{Name: "time", Arguments: `{}`}},
+		),
+		groq.ChatCompletionResponse{
+			Choices: []groq.ChatCompletionChoice{{
+				Message:      groq.ChatCompletionMessage{Role: groq.RoleAssistant, Content: "done"},
+				FinishReason: groq.ReasonStop,
+			}},
+		},
+	)
+	defer teardown()
+	agent := groq.NewAgent(
+		client,
+		groq.WithToolHandler(timeTool, func(context.Context, tools.ToolCall) (string, error) {
+			return "noon", nil
+		}),
+		groq.WithToolHandler(timeTool, func(context.Context, tools.ToolCall) (string, error) {
+			return "midnight", nil
+		}),
+	)
+	result, err := agent.Run(context.Background(), groq.ChatCompletionRequest{
+		Model: groq.ModelLlama3Groq70B8192ToolUsePreview,
+		Messages: []groq.ChatCompletionMessage{
+			{Role: groq.RoleUser, Content: "What time is it?"},
+		},
+	})
+	a.NoError(err)
+	a.Equal("midnight", result.Messages[2].Content)
+	a.Len((*requests)[0].Tools, 1)
+}
+
+// TestAgentToolErrors tests that failing and unknown tools are reported to
+// the model instead of aborting the run.
+func TestAgentToolErrors(t *testing.T) {
+	a := assert.New(t)
+	client, _, teardown := setupAgentTestServer(t,
+		toolCallsResponse(
+			tools.ToolCall{ID: "call_1", Function: tools.FunctionCall{Name: "weather"}},
+			tools.ToolCall{ID: "call_2", Function: tools.FunctionCall{Name: "missing"}},
+		),
+		groq.ChatCompletionResponse{
+			Choices: []groq.ChatCompletionChoice{{
+				Message: groq.ChatCompletionMessage{Role: groq.RoleAssistant, Content: "sorry"},
+			}},
+		},
+	)
+	defer teardown()
+	var errs []error
+	var mu sync.Mutex
+	agent := groq.NewAgent(
+		client,
+		groq.WithToolHandler(weatherTool, func(context.Context, tools.ToolCall) (string, error) {
+			return "", errors.New("service down")
+		}),
+		groq.WithAfterToolCall(func(_ context.Context, _ tools.ToolCall, _ groq.ChatCompletionMessage, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		}),
+	)
+	result, err := agent.Run(context.Background(), groq.ChatCompletionRequest{
+		Model:             groq.ModelLlama3Groq70B8192ToolUsePreview,
+		Messages:          []groq.ChatCompletionMessage{{Role: groq.RoleUser, Content: "Hi"}},
+		ParallelToolCalls: false,
+	})
+	a.NoError(err)
+	a.Len(errs, 2)
+	a.Contains(result.Messages[2].Content, "service down")
+	a.Contains(result.Messages[3].Content, "tool missing not found")
+}
+
+// TestAgentLimits tests the iteration and token budgets of the agent.
+func TestAgentLimits(t *testing.T) {
+	a := assert.New(t)
+	client, _, teardown := setupAgentTestServer(t,
+		toolCallsResponse(
+			tools.ToolCall{ID: "call_1", Function: tools.FunctionCall{Name: "time"}},
+		),
+	)
+	defer teardown()
+	handler := groq.WithToolHandler(timeTool, func(context.Context, tools.ToolCall) (string, error) {
+		return "noon", nil
+	})
+	request := groq.ChatCompletionRequest{
+		Model:    groq.ModelLlama3Groq70B8192ToolUsePreview,
+		Messages: []groq.ChatCompletionMessage{{Role: groq.RoleUser, Content: "Hi"}},
+	}
+	result, err := groq.NewAgent(client, handler, groq.WithMaxIterations(3)).
+		Run(context.Background(), request)
+	a.ErrorAs(err, &groqerr.ErrAgentMaxIterations{})
+	a.Equal(3, result.Iterations)
+	result, err = groq.NewAgent(client, handler, groq.WithTokenBudget(20)).
+		Run(context.Background(), request)
+	a.ErrorAs(err, &groqerr.ErrAgentTokenBudget{})
+	a.Equal(2, result.Iterations)
+}
+
+// staticProvider is a tool provider serving a single tool.
+type staticProvider struct{}
+
+func (staticProvider) Tools(context.Context) ([]tools.Tool, error) {
+	return []tools.Tool{timeTool}, nil
+}
+
+func (staticProvider) RunTool(
+	_ context.Context,
+	call tools.ToolCall,
+) (groq.ChatCompletionMessage, error) {
+	return groq.ChatCompletionMessage{
+		Role:    groq.RoleFunction,
+		Content: "provided " + call.Function.Name,
+	}, nil
+}
+
+// TestAgentToolProvider tests that tool providers are dispatched to.
+func TestAgentToolProvider(t *testing.T) {
+	a := assert.New(t)
+	client, requests, teardown := setupAgentTestServer(t,
+		toolCallsResponse(
+			tools.ToolCall{ID: "call_1", Function: tools.FunctionCall{Name: "time"}},
+		),
+		groq.ChatCompletionResponse{
+			Choices: []groq.ChatCompletionChoice{{
+				Message: groq.ChatCompletionMessage{Role: groq.RoleAssistant, Content: "done"},
+			}},
+		},
+	)
+	defer teardown()
+	result, err := groq.NewAgent(client, groq.WithToolProvider(staticProvider{})).
+		Run(context.Background(), groq.ChatCompletionRequest{
+			Model:    groq.ModelLlama3Groq70B8192ToolUsePreview,
+			Messages: []groq.ChatCompletionMessage{{Role: groq.RoleUser, Content: "Hi"}},
+		})
+	a.NoError(err)
+	a.Equal("time", (*requests)[0].Tools[0].Function.Name)
+	a.Equal(groq.RoleTool, result.Messages[2].Role)
+	a.Equal("call_1", result.Messages[2].ToolCallID)
+	a.Equal("provided time", result.Messages[2].Content)
+}
@@ -0,0 +1,308 @@
+package groq
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/conneroisu/groq-go/internal/audio"
+)
+
+const (
+	// MaxAudioBytes is the size limit of the audio files uploaded to the
+	// transcriptions and translations endpoints.
+	MaxAudioBytes = 25 << 20
+	// audioFormOverhead is the room left in uploads for the other fields
+	// of the multipart form.
+	audioFormOverhead = 64 << 10
+	// maxOverlapWords is the number of words compared at the ends of
+	// consecutive transcripts to find their overlap.
+	maxOverlapWords = 50
+)
+
+type (
+	// LongAudioOpts is a function that sets options for long audio
+	// transcriptions and translations.
+	LongAudioOpts func(*longAudioConfig)
+	// longAudioConfig is the configuration of long audio transcriptions
+	// and translations.
+	longAudioConfig struct {
+		chunkDuration time.Duration
+		overlap       time.Duration
+		silenceWindow time.Duration
+		maxBytes      int
+		concurrency   int
+	}
+)
+
+// WithChunkDuration sets the longest duration of the chunks of long audio,
+// 10 minutes by default.
+func WithChunkDuration(d time.Duration) LongAudioOpts {
+	return func(c *longAudioConfig) { c.chunkDuration = d }
+}
+
+// WithChunkOverlap sets the duration shared by consecutive chunks of long
+// audio, 3 seconds by default.
+func WithChunkOverlap(d time.Duration) LongAudioOpts {
+	return func(c *longAudioConfig) { c.overlap = d }
+}
+
+// WithSilenceWindow sets the duration before the end of a chunk searched
+// for a silence to cut it at, 10 seconds by default.
+func WithSilenceWindow(d time.Duration) LongAudioOpts {
+	return func(c *longAudioConfig) { c.silenceWindow = d }
+}
+
+// WithMaxChunkBytes sets the size limit of the chunks of long audio, which
+// defaults to MaxAudioBytes minus room for the other fields of the form.
+func WithMaxChunkBytes(n int) LongAudioOpts {
+	return func(c *longAudioConfig) { c.maxBytes = n }
+}
+
+// WithChunkConcurrency sets the number of chunks of long audio sent at
+// once, 4 by default.
+func WithChunkConcurrency(n int) LongAudioOpts {
+	return func(c *longAudioConfig) { c.concurrency = max(1, n) }
+}
+
+// TranscribeLong transcribes wav, mp3 or flac audio of any length.
+//
+// The audio is split into overlapping chunks, cut at silences when
+// possible, that are transcribed concurrently with Transcribe. Their
+// segments and words are merged with their timestamps offset to the
+// position of the chunk and the overlaps deduplicated. The response has the
+// Duration of the whole audio.
+//
+// The srt and vtt formats are not supported.
+func (c *Client) TranscribeLong(
+	ctx context.Context,
+	request AudioRequest,
+	opts ...LongAudioOpts,
+) (AudioResponse, error) {
+	return c.audioLong(ctx, request, c.Transcribe, opts)
+}
+
+// TranslateLong translates wav, mp3 or flac audio of any length the same
+// way TranscribeLong transcribes it.
+func (c *Client) TranslateLong(
+	ctx context.Context,
+	request AudioRequest,
+	opts ...LongAudioOpts,
+) (AudioResponse, error) {
+	return c.audioLong(ctx, request, c.Translate, opts)
+}
+
+// audioLong splits the audio of a request into chunks, calls the audio
+// api with each chunk and merges their responses.
+func (c *Client) audioLong(
+	ctx context.Context,
+	request AudioRequest,
+	call func(context.Context, AudioRequest) (AudioResponse, error),
+	opts []LongAudioOpts,
+) (AudioResponse, error) {
+	cfg := longAudioConfig{
+		chunkDuration: 10 * time.Minute,
+		overlap:       3 * time.Second,
+		silenceWindow: 10 * time.Second,
+		maxBytes:      MaxAudioBytes - audioFormOverhead,
+		concurrency:   4,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if request.Format == FormatSRT || request.Format == FormatVTT {
+		return AudioResponse{}, fmt.Errorf(
+			"long audio can not be returned as %s",
+			request.Format,
+		)
+	}
+	data, err := readAudio(request)
+	if err != nil {
+		return AudioResponse{}, err
+	}
+	chunks, duration, err := audio.Split(data, audio.Options{
+		MaxDuration:   cfg.chunkDuration.Seconds(),
+		MaxBytes:      cfg.maxBytes,
+		Overlap:       cfg.overlap.Seconds(),
+		SilenceWindow: min(cfg.silenceWindow, cfg.chunkDuration/4).Seconds(),
+	})
+	if err != nil {
+		return AudioResponse{}, err
+	}
+	name := strings.TrimSuffix(filepath.Base(request.FilePath), filepath.Ext(request.FilePath))
+	if request.FilePath == "" {
+		name = "audio"
+	}
+	ext := audio.Ext(data)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		responses = make([]AudioResponse, len(chunks))
+		sem       = make(chan struct{}, cfg.concurrency)
+		wg        sync.WaitGroup
+		once      sync.Once
+		callErr   error
+	)
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+			r := request
+			r.Reader = bytes.NewReader(chunk.Data)
+			r.FilePath = fmt.Sprintf("%s.part%d%s", name, i, ext)
+			response, err := call(ctx, r)
+			if err != nil {
+				once.Do(func() {
+					callErr = fmt.Errorf("chunk %d of the audio: %w", i, err)
+					cancel()
+				})
+				return
+			}
+			responses[i] = response
+		}()
+	}
+	wg.Wait()
+	if callErr != nil {
+		return AudioResponse{}, callErr
+	}
+	if err := ctx.Err(); err != nil {
+		return AudioResponse{}, err
+	}
+	return mergeAudioResponses(chunks, responses, duration), nil
+}
+
+// readAudio reads the audio of a request from its reader or file.
+func readAudio(request AudioRequest) ([]byte, error) {
+	if request.Reader != nil {
+		return io.ReadAll(request.Reader)
+	}
+	data, err := os.ReadFile(request.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("opening audio file: %w", err)
+	}
+	return data, nil
+}
+
+// mergeAudioResponses merges the responses of the chunks of audio.
+//
+// Segments and words are kept from the chunk whose side of the middle of
+// an overlap they are centered on. Without segments or words, the text of
+// consecutive chunks is joined at the longest run of words they share.
+func mergeAudioResponses(
+	chunks []audio.Chunk,
+	responses []AudioResponse,
+	duration float64,
+) AudioResponse {
+	merged := AudioResponse{
+		Task:     responses[0].Task,
+		Language: responses[0].Language,
+		Duration: duration,
+		header:   responses[len(responses)-1].header,
+	}
+	var texts []string
+	for i, response := range responses {
+		offset := chunks[i].Start
+		lo, hi := math.Inf(-1), math.Inf(1)
+		if i > 0 {
+			lo = (chunks[i].Start + chunks[i-1].End) / 2
+		}
+		if i < len(chunks)-1 {
+			hi = (chunks[i+1].Start + chunks[i].End) / 2
+		}
+		keep := func(start, end float64) bool {
+			middle := (start + end) / 2
+			return lo <= middle && middle < hi
+		}
+		for _, segment := range response.Segments {
+			segment.Start += offset
+			segment.End += offset
+			if !keep(segment.Start, segment.End) {
+				continue
+			}
+			segment.ID = len(merged.Segments)
+			segment.Seek += int(math.Round(offset * 100))
+			merged.Segments = append(merged.Segments, segment)
+			texts = append(texts, strings.TrimSpace(segment.Text))
+		}
+		for _, word := range response.Words {
+			word.Start += offset
+			word.End += offset
+			if keep(word.Start, word.End) {
+				merged.Words = append(merged.Words, word)
+			}
+		}
+	}
+	switch {
+	case len(merged.Segments) > 0:
+		merged.Text = strings.Join(texts, " ")
+	case len(merged.Words) > 0:
+		words := make([]string, len(merged.Words))
+		for i, word := range merged.Words {
+			words[i] = strings.TrimSpace(word.Word)
+		}
+		merged.Text = strings.Join(words, " ")
+	default:
+		for _, response := range responses {
+			merged.Text = mergeText(merged.Text, response.Text)
+		}
+	}
+	return merged
+}
+
+// mergeText joins two transcripts of overlapping audio at the longest run
+// of words shared by the end of the first and the start of the second,
+// ignoring case and punctuation.
+//
+// Transcripts sharing fewer than two words are joined as they are.
+func mergeText(prev, next string) string {
+	a, b := strings.Fields(prev), strings.Fields(next)
+	if len(a) == 0 || len(b) == 0 {
+		return strings.Join(append(a, b...), " ")
+	}
+	tail := a[max(0, len(a)-maxOverlapWords):]
+	head := b[:min(len(b), maxOverlapWords)]
+	// lengths[i][j] is the length of the run shared by tail[:i] and
+	// head[:j]
+	lengths := make([][]int, len(tail)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(head)+1)
+	}
+	best, endA, endB := 0, 0, 0
+	for i := 1; i <= len(tail); i++ {
+		for j := 1; j <= len(head); j++ {
+			if normalizeWord(tail[i-1]) != normalizeWord(head[j-1]) {
+				continue
+			}
+			lengths[i][j] = lengths[i-1][j-1] + 1
+			if lengths[i][j] >= best {
+				best, endA, endB = lengths[i][j], i, j
+			}
+		}
+	}
+	if best < 2 {
+		return strings.Join(append(a, b...), " ")
+	}
+	kept := a[:len(a)-len(tail)+endA]
+	return strings.Join(append(kept, b[endB:]...), " ")
+}
+
+// normalizeWord returns a word in lower case without punctuation.
+func normalizeWord(word string) string {
+	return strings.ToLower(strings.TrimFunc(word, func(r rune) bool {
+		return unicode.IsPunct(r)
+	}))
+}
@@ -0,0 +1,202 @@
+package groq_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// longAudioRate is the sample rate of the long audio of the tests.
+	longAudioRate = 1000
+	// longAudioSeconds is the duration of the long audio of the tests.
+	longAudioSeconds = 30
+)
+
+// longAudioSilences are the silent seconds of the long audio.
+var longAudioSilences = []int{7, 15, 23}
+
+// longAudio returns a wav file whose 100ms windows have the sample 1000+i,
+// i being the index of the window, except in the silent seconds.
+func longAudio() []byte {
+	body := make([]byte, 0, 2*longAudioRate*longAudioSeconds)
+	for i := range longAudioRate * longAudioSeconds {
+		sample := 1000 + i/100
+		if slices.Contains(longAudioSilences, i/longAudioRate) {
+			sample = 0
+		}
+		body = binary.LittleEndian.AppendUint16(body, uint16(sample))
+	}
+	header := []byte("RIFF\x00\x00\x00\x00WAVEfmt \x10\x00\x00\x00\x01\x00\x01\x00")
+	header = binary.LittleEndian.AppendUint32(header, longAudioRate)
+	header = binary.LittleEndian.AppendUint32(header, 2*longAudioRate)
+	header = append(header, 2, 0, 16, 0)
+	header = append(header, "data"...)
+	header = binary.LittleEndian.AppendUint32(header, uint32(len(body)))
+	binary.LittleEndian.PutUint32(header[4:], uint32(36+len(body)))
+	return append(header, body...)
+}
+
+// transcribeWindows transcribes a chunk of the long audio with a word per
+// window and a segment per second, timed relative to the chunk.
+func transcribeWindows(data []byte) groq.AudioResponse {
+	var response groq.AudioResponse
+	samples := data[44:]
+	at := func(i int) int { return int(binary.LittleEndian.Uint16(samples[2*i:])) }
+	n := len(samples) / 2
+	for i := 0; i < n; {
+		j := i
+		for j < n && at(j) == at(i) {
+			j++
+		}
+		if at(i) != 0 {
+			window := at(i) - 1000
+			start, end := float64(i)/longAudioRate, float64(j)/longAudioRate
+			response.Words = append(response.Words, groq.Words{{
+				Word: fmt.Sprintf("w%d", window), Start: start, End: end,
+			}}...)
+			second := window / 10
+			last := len(response.Segments) - 1
+			if last >= 0 && response.Segments[last].Text == fmt.Sprintf("s%d", second) {
+				response.Segments[last].End = end
+			} else {
+				response.Segments = append(response.Segments, groq.Segments{{
+					Text: fmt.Sprintf("s%d", second), Start: start, End: end,
+				}}...)
+			}
+		}
+		i = j
+	}
+	words := make([]string, len(response.Words))
+	for i, word := range response.Words {
+		words[i] = word.Word
+	}
+	response.Text = strings.Join(words, " ")
+	response.Duration = float64(n) / longAudioRate
+	return response
+}
+
+// setupLongAudioTestServer sets up a test server transcribing the chunks
+// of the long audio and returns the number of chunks it received.
+func setupLongAudioTestServer(t *testing.T) (*groq.Client, *atomic.Int32, func()) {
+	t.Helper()
+	client, server, teardown := setupGroqTestServer()
+	var calls atomic.Int32
+	server.RegisterHandler("/v1/audio/transcriptions", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !strings.HasSuffix(header.Filename, ".wav") {
+			http.Error(w, "bad file name "+header.Filename, http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(file)
+		response := transcribeWindows(data)
+		if r.FormValue("response_format") != string(groq.FormatVerboseJSON) {
+			response = groq.AudioResponse{Text: response.Text}
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	})
+	return client, &calls, teardown
+}
+
+// expectedWindows returns the words and segments of the whole long audio.
+func expectedWindows() (words, segments []string) {
+	for i := range longAudioSeconds * 10 {
+		if slices.Contains(longAudioSilences, i/10) {
+			continue
+		}
+		words = append(words, fmt.Sprintf("w%d", i))
+		if i%10 == 0 {
+			segments = append(segments, fmt.Sprintf("s%d", i/10))
+		}
+	}
+	return words, segments
+}
+
+// TestTranscribeLong tests that the chunks of long audio are merged with
+// their timestamps offset and their overlaps deduplicated.
+func TestTranscribeLong(t *testing.T) {
+	a := assert.New(t)
+	client, calls, teardown := setupLongAudioTestServer(t)
+	defer teardown()
+	response, err := client.TranscribeLong(
+		context.Background(),
+		groq.AudioRequest{
+			Model:    groq.ModelWhisperLargeV3,
+			FilePath: "long.wav",
+			Reader:   bytes.NewReader(longAudio()),
+			Format:   groq.FormatVerboseJSON,
+		},
+		groq.WithChunkDuration(10*time.Second),
+		groq.WithChunkOverlap(2*time.Second),
+		groq.WithSilenceWindow(3*time.Second),
+	)
+	require.NoError(t, err)
+	a.Greater(calls.Load(), int32(2))
+	a.InDelta(float64(longAudioSeconds), response.Duration, 1e-9)
+	words, segments := expectedWindows()
+	require.Len(t, response.Words, len(words))
+	for i, word := range response.Words {
+		a.Equal(words[i], word.Word)
+		var window int
+		_, _ = fmt.Sscanf(word.Word, "w%d", &window)
+		a.InDelta(float64(window)/10, word.Start, 1e-6, word.Word)
+	}
+	require.Len(t, response.Segments, len(segments))
+	for i, segment := range response.Segments {
+		a.Equal(i, segment.ID)
+		a.Equal(segments[i], segment.Text)
+	}
+	a.Equal(strings.Join(segments, " "), response.Text)
+}
+
+// TestTranscribeLongText tests that the text of the chunks of long audio is
+// deduplicated without timestamps.
+func TestTranscribeLongText(t *testing.T) {
+	a := assert.New(t)
+	client, calls, teardown := setupLongAudioTestServer(t)
+	defer teardown()
+	response, err := client.TranscribeLong(
+		context.Background(),
+		groq.AudioRequest{
+			Model:  groq.ModelWhisperLargeV3,
+			Reader: bytes.NewReader(longAudio()),
+		},
+		groq.WithChunkDuration(10*time.Second),
+		groq.WithChunkOverlap(2*time.Second),
+		groq.WithChunkConcurrency(1),
+	)
+	require.NoError(t, err)
+	a.Greater(calls.Load(), int32(2))
+	words, _ := expectedWindows()
+	a.Equal(strings.Join(words, " "), response.Text)
+
+	_, err = client.TranscribeLong(context.Background(), groq.AudioRequest{
+		Model:  groq.ModelWhisperLargeV3,
+		Reader: bytes.NewReader(longAudio()),
+		Format: groq.FormatSRT,
+	})
+	a.Error(err)
+	_, err = client.TranscribeLong(context.Background(), groq.AudioRequest{
+		Model:  groq.ModelWhisperLargeV3,
+		Reader: strings.NewReader("not audio"),
+	})
+	a.Error(err)
+}
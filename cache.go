@@ -0,0 +1,434 @@
+package groq
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/conneroisu/groq-go/internal/list"
+	"github.com/conneroisu/groq-go/internal/streams"
+	"github.com/conneroisu/groq-go/pkg/tools"
+)
+
+// cacheKeyVersion prefixes the hashed requests so that changes to the key
+// derivation do not hit entries stored by older versions.
+const cacheKeyVersion = "groq-go/cache/v1\n"
+
+type (
+	// Cache stores the responses of chat completions keyed by the hash of
+	// their requests.
+	//
+	// Implementations must be safe for concurrent use.
+	Cache interface {
+		// Get returns the value stored for the key and whether it was
+		// found and has not expired.
+		Get(ctx context.Context, key string) ([]byte, bool, error)
+		// Set stores the value for the key, expiring it after ttl. A ttl
+		// of zero never expires the value.
+		Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	}
+	// CacheOpts is a function that sets options for the cache of a Groq
+	// client.
+	CacheOpts func(*cacheConfig)
+	// cacheConfig is the configuration of the cache of a client.
+	cacheConfig struct {
+		ttl       time.Duration
+		cacheable func(ChatCompletionRequest) bool
+	}
+	// LRUCache is an in-memory Cache evicting the least recently used
+	// entries once it holds more than its size.
+	//
+	// It is safe for concurrent use.
+	LRUCache struct {
+		size    int
+		mu      sync.Mutex
+		order   *list.List[*lruEntry]
+		entries map[string]*list.Element[*lruEntry]
+	}
+	// lruEntry is an entry of an LRUCache.
+	lruEntry struct {
+		key     string
+		value   []byte
+		expires time.Time
+	}
+	// FileCache is a Cache storing each entry as a file of a directory.
+	//
+	// It is safe for concurrent use, including by several processes sharing
+	// the directory.
+	FileCache struct {
+		dir string
+	}
+	// fileEntry is the content of a file of a FileCache.
+	fileEntry struct {
+		Expires time.Time `json:"expires"`
+		Value   []byte    `json:"value"`
+	}
+)
+
+// WithCache sets the cache the Groq client stores the responses of chat
+// completions in.
+//
+// The cache is a middleware appended to the chain of the client, so it sits
+// after the middleware of previous options. Cached responses are returned
+// to both ChatCompletion and ChatCompletionStream, the latter replaying them
+// as stream chunks. By default only deterministic requests, those with a
+// seed, are cached and entries never expire. WithCacheable opts other
+// requests in.
+func WithCache(cache Cache, opts ...CacheOpts) Opts {
+	cfg := cacheConfig{cacheable: IsDeterministic}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return func(c *Client) {
+		c.middleware = append(c.middleware, c.cacheMiddleware(cache, cfg))
+	}
+}
+
+// WithCacheTTL sets the time after which cached responses expire.
+func WithCacheTTL(ttl time.Duration) CacheOpts {
+	return func(cfg *cacheConfig) { cfg.ttl = ttl }
+}
+
+// WithCacheable sets the function deciding whether the response of a
+// request is cached, replacing IsDeterministic.
+func WithCacheable(cacheable func(ChatCompletionRequest) bool) CacheOpts {
+	return func(cfg *cacheConfig) { cfg.cacheable = cacheable }
+}
+
+// IsDeterministic reports whether a request has a seed, which are the
+// requests cached by default.
+//
+// A temperature of zero is omitted from the request like an unset one, so
+// the api samples it at its default temperature of 1 and it is not
+// deterministic.
+func IsDeterministic(request ChatCompletionRequest) bool {
+	return request.Seed != nil
+}
+
+// CacheKey returns the key of a request in a Cache.
+//
+// The key is the hash of the request without the fields not changing its
+// response: Stream, StreamOptions and User.
+func CacheKey(request ChatCompletionRequest) (string, error) {
+	request.Stream = false
+	request.StreamOptions = nil
+	request.User = ""
+	b, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("error hashing request: %w", err)
+	}
+	sum := sha256.Sum256(append([]byte(cacheKeyVersion), b...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// cacheMiddleware returns the middleware answering chat completions from
+// the cache and storing their responses in it.
+//
+// Errors of the cache are logged and treated as misses.
+func (c *Client) cacheMiddleware(cache Cache, cfg cacheConfig) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (any, error) {
+			stream := call.Operation == "ChatCompletionStream"
+			if !stream && call.Operation != "ChatCompletion" {
+				return next(ctx, call)
+			}
+			request, ok := call.Request.(*ChatCompletionRequest)
+			if !ok || request == nil || !cfg.cacheable(*request) {
+				return next(ctx, call)
+			}
+			key, err := CacheKey(*request)
+			if err != nil {
+				c.logger.Warn("skipping cache", "error", err)
+				return next(ctx, call)
+			}
+			if response, ok := c.cached(ctx, cache, key); ok {
+				response.AnsweredBy = request.Model
+				if stream {
+					return replayStream(response)
+				}
+				return response, nil
+			}
+			res, err := next(ctx, call)
+			if err != nil {
+				return res, err
+			}
+			switch res := res.(type) {
+			case ChatCompletionResponse:
+				c.store(ctx, cache, key, cfg.ttl, res)
+			case *ChatCompletionStream:
+				var acc ChatCompletionAccumulator
+				stored := false
+				res.Observe(func(chunk *ChatCompletionStreamResponse, err error) {
+					switch {
+					case err == nil:
+						acc.Add(chunk)
+					case errors.Is(err, io.EOF) && !stored:
+						stored = true
+						c.store(ctx, cache, key, cfg.ttl, acc.Response())
+					default:
+						// a failed stream is never stored
+						stored = true
+					}
+				}, nil)
+			}
+			return res, err
+		}
+	}
+}
+
+// cached returns the response stored in the cache for the key.
+func (c *Client) cached(
+	ctx context.Context,
+	cache Cache,
+	key string,
+) (response ChatCompletionResponse, ok bool) {
+	value, ok, err := cache.Get(ctx, key)
+	if err != nil {
+		c.logger.Warn("error reading cache", "key", key, "error", err)
+		return response, false
+	}
+	if !ok {
+		return response, false
+	}
+	err = json.Unmarshal(value, &response)
+	if err != nil {
+		c.logger.Warn("error decoding cached response", "key", key, "error", err)
+		return response, false
+	}
+	return response, true
+}
+
+// store stores a response in the cache for the key.
+func (c *Client) store(
+	ctx context.Context,
+	cache Cache,
+	key string,
+	ttl time.Duration,
+	response ChatCompletionResponse,
+) {
+	value, err := json.Marshal(response)
+	if err == nil {
+		err = cache.Set(context.WithoutCancel(ctx), key, value, ttl)
+	}
+	if err != nil {
+		c.logger.Warn("error writing cache", "key", key, "error", err)
+	}
+}
+
+// replayStream returns a stream replaying a response as the chunks of a
+// chat completion stream.
+//
+// Each choice is sent as a single chunk and the usage of the response is
+// sent in the x_groq field of a last chunk. Events are not separated by
+// blank lines, which would count as empty messages of the stream.
+func replayStream(response ChatCompletionResponse) (*ChatCompletionStream, error) {
+	var body bytes.Buffer
+	write := func(chunk ChatCompletionStreamResponse) error {
+		chunk.ID = response.ID
+		chunk.Object = "chat.completion.chunk"
+		chunk.Created = response.Created
+		chunk.Model = response.Model
+		chunk.SystemFingerprint = response.SystemFingerprint
+		b, err := json.Marshal(chunk)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(&body, "data: %s\n", b)
+		return err
+	}
+	for _, choice := range response.Choices {
+		message := choice.Message
+		toolCalls := make([]tools.ToolCall, len(message.ToolCalls))
+		for i, call := range message.ToolCalls {
+			index := i
+			call.Index = &index
+			toolCalls[i] = call
+		}
+		err := write(ChatCompletionStreamResponse{
+			Choices: []ChatCompletionStreamChoice{{
+				Index: choice.Index,
+				Delta: ChatCompletionStreamChoiceDelta{
+					Content:      message.Content,
+					Role:         string(message.Role),
+					FunctionCall: message.FunctionCall,
+					ToolCalls:    toolCalls,
+				},
+				FinishReason: choice.FinishReason,
+				LogProbs:     choice.LogProbs,
+			}},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	usage := response.Usage
+	err := write(ChatCompletionStreamResponse{
+		Choices: []ChatCompletionStreamChoice{},
+		XGroq:   &XGroq{Usage: &usage},
+	})
+	if err != nil {
+		return nil, err
+	}
+	body.WriteString("data: [DONE]\n")
+	return &ChatCompletionStream{
+		StreamReader: streams.NewStreamReader[ChatCompletionStreamResponse](
+			io.NopCloser(&body),
+			http.Header{},
+			0,
+		),
+		AnsweredBy: response.AnsweredBy,
+	}, nil
+}
+
+// NewLRUCache creates an in-memory cache holding at most size entries.
+//
+// A size of zero or less does not limit the number of entries.
+func NewLRUCache(size int) *LRUCache {
+	return &LRUCache{
+		size:    size,
+		order:   list.New[*lruEntry](),
+		entries: make(map[string]*list.Element[*lruEntry]),
+	}
+}
+
+// Get returns the value stored for the key and whether it was found and
+// has not expired.
+func (l *LRUCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	element, ok := l.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := element.Value
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		l.order.Remove(element)
+		delete(l.entries, key)
+		return nil, false, nil
+	}
+	l.order.MoveToFront(element)
+	return bytes.Clone(entry.value), true, nil
+}
+
+// Set stores the value for the key, evicting the least recently used entry
+// if the cache is full.
+func (l *LRUCache) Set(
+	_ context.Context,
+	key string,
+	value []byte,
+	ttl time.Duration,
+) error {
+	entry := &lruEntry{key: key, value: bytes.Clone(value)}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if element, ok := l.entries[key]; ok {
+		element.Value = entry
+		l.order.MoveToFront(element)
+		return nil
+	}
+	l.entries[key] = l.order.PushFront(entry)
+	for l.size > 0 && l.order.Len() > l.size {
+		oldest := l.order.Remove(l.order.Back())
+		delete(l.entries, oldest.key)
+	}
+	return nil
+}
+
+// Len returns the number of entries of the cache, including the expired
+// entries not yet evicted.
+func (l *LRUCache) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+// NewFileCache creates a cache storing its entries in the directory,
+// creating it if it does not exist.
+func NewFileCache(dir string) (*FileCache, error) {
+	err := os.MkdirAll(dir, 0o750)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cache directory: %w", err)
+	}
+	return &FileCache{dir: dir}, nil
+}
+
+// Get returns the value stored for the key and whether it was found and
+// has not expired.
+//
+// Expired entries are removed.
+func (f *FileCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	path := f.path(key)
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	var entry fileEntry
+	err = json.Unmarshal(b, &entry)
+	if err != nil {
+		return nil, false, fmt.Errorf("error decoding cache entry %s: %w", path, err)
+	}
+	if !entry.Expires.IsZero() && time.Now().After(entry.Expires) {
+		err = os.Remove(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, false, err
+		}
+		return nil, false, nil
+	}
+	return entry.Value, true, nil
+}
+
+// Set stores the value for the key.
+//
+// The entry is written to a temporary file renamed over the previous entry
+// so that readers never see a partial entry.
+func (f *FileCache) Set(
+	_ context.Context,
+	key string,
+	value []byte,
+	ttl time.Duration,
+) error {
+	entry := fileEntry{Value: value}
+	if ttl > 0 {
+		entry.Expires = time.Now().Add(ttl)
+	}
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(f.dir, ".entry-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(b)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path(key))
+}
+
+// path returns the path of the file of a key, hashed so that any key is a
+// valid file name.
+func (f *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(f.dir, hex.EncodeToString(sum[:])+".json")
+}
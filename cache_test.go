@@ -0,0 +1,255 @@
+package groq_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/conneroisu/groq-go/pkg/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupCacheTestServer sets up a test server answering chat completions,
+// streamed or not, and returns the number of requests it received.
+func setupCacheTestServer(
+	t *testing.T,
+	opts ...groq.Opts,
+) (*groq.Client, *atomic.Int32, func()) {
+	t.Helper()
+	client, server, teardown := setupGroqTestServer(
+		append([]groq.Opts{groq.WithRetryPolicy(groq.NoRetryPolicy())}, opts...)...,
+	)
+	var calls atomic.Int32
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			var req groq.ChatCompletionRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if !req.Stream {
+				response := jsonReply("hello")
+				response.Model = req.Model
+				_ = json.NewEncoder(w).Encode(response)
+				return
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			index := 0
+			chunks := []groq.ChatCompletionStreamResponse{
+				{ID: "chatcmpl-stream", Model: req.Model, Choices: []groq.ChatCompletionStreamChoice{{
+					Delta: groq.ChatCompletionStreamChoiceDelta{Role: "assistant", Content: "hel"},
+				}}},
+				{ID: "chatcmpl-stream", Model: req.Model, Choices: []groq.ChatCompletionStreamChoice{{
+					Delta: groq.ChatCompletionStreamChoiceDelta{
+						Content: "lo",
+						ToolCalls: []tools.ToolCall{{
+							Index:    &index,
+							ID:       "call_1",
+							Type:     "function",
+							Function: tools.FunctionCall{Name: "lookup", Arguments: "{}"},
+						}},
+					},
+					FinishReason: groq.ReasonStop,
+				}}, XGroq: &groq.XGroq{Usage: &groq.Usage{PromptTokens: 4, CompletionTokens: 2, TotalTokens: 6}}},
+			}
+			for _, chunk := range chunks {
+				b, _ := json.Marshal(chunk)
+				_, _ = fmt.Fprintf(w, "data: %s\n\n", b)
+			}
+			_, _ = io.WriteString(w, "data: [DONE]\n\n")
+		},
+	)
+	return client, &calls, teardown
+}
+
+// cacheRequest returns a deterministic chat completion request.
+func cacheRequest() groq.ChatCompletionRequest {
+	seed := 42
+	return groq.ChatCompletionRequest{
+		Model:    groq.ModelLlama38B8192,
+		Messages: []groq.ChatCompletionMessage{{Role: groq.RoleUser, Content: "hi"}},
+		Seed:     &seed,
+	}
+}
+
+// TestCacheChatCompletion tests that deterministic chat completions are
+// answered from the cache.
+func TestCacheChatCompletion(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	client, calls, teardown := setupCacheTestServer(
+		t,
+		groq.WithCache(groq.NewLRUCache(10)),
+	)
+	defer teardown()
+	first, err := client.ChatCompletion(ctx, cacheRequest())
+	require.NoError(t, err)
+	request := cacheRequest()
+	request.User = "someone"
+	second, err := client.ChatCompletion(ctx, request)
+	require.NoError(t, err)
+	a.Equal(int32(1), calls.Load())
+	a.Equal(first.Choices, second.Choices)
+	a.Equal(groq.ModelLlama38B8192, second.AnsweredBy)
+
+	request.Temperature = 0.7
+	_, err = client.ChatCompletion(ctx, request)
+	require.NoError(t, err)
+	_, err = client.ChatCompletion(ctx, request)
+	require.NoError(t, err)
+	a.Equal(int32(2), calls.Load(), "requests with a seed should be cached")
+
+	request = cacheRequest()
+	request.Seed = nil
+	_, err = client.ChatCompletion(ctx, request)
+	require.NoError(t, err)
+	_, err = client.ChatCompletion(ctx, request)
+	require.NoError(t, err)
+	a.Equal(int32(4), calls.Load(), "requests without a seed should not be cached")
+}
+
+// TestCacheable tests that requests without a seed are cached once opted
+// in.
+func TestCacheable(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	client, calls, teardown := setupCacheTestServer(
+		t,
+		groq.WithCache(
+			groq.NewLRUCache(10),
+			groq.WithCacheable(func(groq.ChatCompletionRequest) bool { return true }),
+		),
+	)
+	defer teardown()
+	request := cacheRequest()
+	request.Seed = nil
+	a.False(groq.IsDeterministic(request), "a zero temperature is not sent")
+	_, err := client.ChatCompletion(ctx, request)
+	require.NoError(t, err)
+	_, err = client.ChatCompletion(ctx, request)
+	require.NoError(t, err)
+	a.Equal(int32(1), calls.Load())
+}
+
+// TestCacheChatCompletionStream tests that streamed chat completions are
+// stored in the cache and replayed as stream chunks.
+func TestCacheChatCompletionStream(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	client, calls, teardown := setupCacheTestServer(
+		t,
+		groq.WithCache(groq.NewLRUCache(10)),
+	)
+	defer teardown()
+	stream, err := client.ChatCompletionStream(ctx, cacheRequest())
+	require.NoError(t, err)
+	streamed, err := stream.Accumulate()
+	require.NoError(t, err)
+
+	cached, err := client.ChatCompletion(ctx, cacheRequest())
+	require.NoError(t, err)
+	a.Equal(streamed.Choices, cached.Choices)
+
+	stream, err = client.ChatCompletionStream(ctx, cacheRequest())
+	require.NoError(t, err)
+	a.Equal(groq.ModelLlama38B8192, stream.AnsweredBy)
+	replayed, err := stream.Accumulate()
+	require.NoError(t, err)
+	a.Equal(int32(1), calls.Load())
+	a.Equal("hello", replayed.Choices[0].Message.Content)
+	a.Equal(groq.ReasonStop, replayed.Choices[0].FinishReason)
+	require.Len(t, replayed.Choices[0].Message.ToolCalls, 1)
+	a.Equal("lookup", replayed.Choices[0].Message.ToolCalls[0].Function.Name)
+	a.Equal(6, replayed.Usage.TotalTokens)
+	a.Equal("chatcmpl-stream", replayed.ID)
+}
+
+// TestCacheKey tests that the key of a request ignores the fields not
+// changing its response.
+func TestCacheKey(t *testing.T) {
+	a := assert.New(t)
+	key, err := groq.CacheKey(cacheRequest())
+	require.NoError(t, err)
+	request := cacheRequest()
+	request.Stream = true
+	request.StreamOptions = &groq.StreamOptions{IncludeUsage: true}
+	request.User = "someone"
+	request.RetryDelay = time.Second
+	other, err := groq.CacheKey(request)
+	require.NoError(t, err)
+	a.Equal(key, other)
+	request.MaxTokens = 10
+	other, err = groq.CacheKey(request)
+	require.NoError(t, err)
+	a.NotEqual(key, other)
+}
+
+// TestLRUCache tests the eviction and expiry of the in-memory cache.
+func TestLRUCache(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	cache := groq.NewLRUCache(2)
+	require.NoError(t, cache.Set(ctx, "a", []byte("1"), 0))
+	require.NoError(t, cache.Set(ctx, "b", []byte("2"), 0))
+	_, ok, err := cache.Get(ctx, "a")
+	require.NoError(t, err)
+	a.True(ok)
+	require.NoError(t, cache.Set(ctx, "c", []byte("3"), 0))
+	a.Equal(2, cache.Len())
+	_, ok, _ = cache.Get(ctx, "b")
+	a.False(ok, "least recently used entry should be evicted")
+	value, ok, _ := cache.Get(ctx, "a")
+	a.True(ok)
+	a.Equal([]byte("1"), value)
+
+	require.NoError(t, cache.Set(ctx, "d", []byte("4"), time.Millisecond))
+	time.Sleep(5 * time.Millisecond)
+	_, ok, _ = cache.Get(ctx, "d")
+	a.False(ok, "expired entry should be missed")
+}
+
+// TestFileCache tests that the filesystem cache persists and expires its
+// entries.
+func TestFileCache(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	cache, err := groq.NewFileCache(dir)
+	require.NoError(t, err)
+	_, ok, err := cache.Get(ctx, "missing")
+	require.NoError(t, err)
+	a.False(ok)
+	require.NoError(t, cache.Set(ctx, "a/b", []byte("1"), 0))
+	require.NoError(t, cache.Set(ctx, "short", []byte("2"), time.Millisecond))
+
+	reopened, err := groq.NewFileCache(dir)
+	require.NoError(t, err)
+	value, ok, err := reopened.Get(ctx, "a/b")
+	require.NoError(t, err)
+	a.True(ok)
+	a.Equal([]byte("1"), value)
+	time.Sleep(5 * time.Millisecond)
+	_, ok, err = reopened.Get(ctx, "short")
+	require.NoError(t, err)
+	a.False(ok)
+
+	client, calls, teardown := setupCacheTestServer(
+		t,
+		groq.WithCache(cache, groq.WithCacheTTL(time.Hour)),
+	)
+	defer teardown()
+	for range 2 {
+		_, err = client.ChatCompletion(ctx, cacheRequest())
+		require.NoError(t, err)
+	}
+	a.Equal(int32(1), calls.Load())
+}
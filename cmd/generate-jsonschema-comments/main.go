@@ -0,0 +1,108 @@
+// Package main is the main package for the jsonschema comment generator.
+//
+// It bakes the Go doc comments of a package tree into a generated Go file
+// so that binaries shipping without source can still describe their
+// schemas with them.
+//
+// Usage from a go:generate directive:
+//
+//	//go:generate go run github.com/conneroisu/groq-go/cmd/generate-jsonschema-comments -out jsonschema_comments.go
+//
+// The generated map is then set as the reflector's comment map:
+//
+//	r := &jsonschema.Reflector{CommentMap: JSONSchemaComments}
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/conneroisu/groq-go/pkg/jsonschema"
+)
+
+// main is the entry point for the application.
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// run runs the main function.
+func run(args []string) error {
+	flags := flag.NewFlagSet("generate-jsonschema-comments", flag.ContinueOnError)
+	var (
+		dir     = flags.String("dir", ".", "root directory of the package tree to read comments from")
+		pkgPath = flags.String("pkg", "", "import path of dir (defaults to the output of go list)")
+		pkgName = flags.String("package", os.Getenv("GOPACKAGE"), "package name of the generated file")
+		varName = flags.String("var", "JSONSchemaComments", "name of the generated comment map variable")
+		out     = flags.String("out", "jsonschema_comments.go", "path of the generated file")
+	)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *pkgName == "" {
+		return fmt.Errorf("-package is required outside of go generate")
+	}
+	if *pkgPath == "" {
+		path, err := importPath(*dir)
+		if err != nil {
+			return err
+		}
+		*pkgPath = path
+	}
+	comments, err := jsonschema.ExtractGoComments(*pkgPath, *dir)
+	if err != nil {
+		return err
+	}
+	src, err := generate(*pkgName, *varName, comments)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(*out, src, 0o644)
+}
+
+// importPath returns the import path of the package in dir.
+func importPath(dir string) (string, error) {
+	cmd := exec.Command("go", "list", "-f", "{{.ImportPath}}", ".")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find import path of %s: %w", dir, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// generate returns the formatted source of the file declaring the comment
+// map.
+func generate(
+	pkgName, varName string,
+	comments map[string]string,
+) ([]byte, error) {
+	keys := make([]string, 0, len(comments))
+	for key := range comments {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by generate-jsonschema-comments DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkgName)
+	fmt.Fprintf(
+		&buf,
+		"// %s maps the types and fields of the package to their Go doc\n"+
+			"// comments for use as a jsonschema.Reflector CommentMap.\n",
+		varName,
+	)
+	fmt.Fprintf(&buf, "var %s = map[string]string{\n", varName)
+	for _, key := range keys {
+		fmt.Fprintf(&buf, "%q: %q,\n", key, comments[key])
+	}
+	buf.WriteString("}\n")
+	return format.Source(buf.Bytes())
+}
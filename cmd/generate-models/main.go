@@ -44,6 +44,7 @@ type (
 	CategorizedModels struct {
 		ChatModels       []ResponseModel
 		AudioModels      []ResponseModel
+		SpeechModels     []ResponseModel
 		ModerationModels []ResponseModel
 	}
 )
@@ -155,6 +156,10 @@ func (r *Response) Categorize() (CategorizedModels, error) {
 			models.ModerationModels = append(models.ModerationModels, model)
 			continue
 		}
+		if model.IsSpeech() {
+			models.SpeechModels = append(models.SpeechModels, model)
+			continue
+		}
 		if model.ContextWindow >= 1024 {
 			models.ChatModels = append(models.ChatModels, model)
 			continue
@@ -164,6 +169,58 @@ func (r *Response) Categorize() (CategorizedModels, error) {
 	return models, nil
 }
 
+// IsSpeech returns whether the model is a text to speech model, whose
+// context window would otherwise categorize it as a chat model.
+func (m ResponseModel) IsSpeech() bool {
+	return strings.Contains(m.ID, "tts")
+}
+
+// ChatCapabilities returns the names of the capability constants of a chat
+// model.
+//
+// The models endpoint does not report capabilities, so they are taken from
+// chatCapabilities. Models missing from the table fall back to chat,
+// streaming and json, which groq serves for every chat model, and are not
+// assumed to call tools or read images until they are added to it.
+func (m ResponseModel) ChatCapabilities() []string {
+	capabilities := []string{
+		"CapabilityChat",
+		"CapabilityStreaming",
+		"CapabilityJSON",
+	}
+	return append(capabilities, chatCapabilities[m.ID]...)
+}
+
+// AudioCapabilities returns the names of the capability constants of an
+// audio model.
+//
+// English only and turbo whisper models do not support translation.
+func (m ResponseModel) AudioCapabilities() []string {
+	capabilities := []string{"CapabilityTranscription"}
+	if !strings.HasSuffix(m.ID, "-en") && !strings.Contains(m.ID, "turbo") {
+		capabilities = append(capabilities, "CapabilityTranslation")
+	}
+	return capabilities
+}
+
+// chatCapabilities are the capabilities beyond chat, streaming and json of
+// the chat models, as documented by groq, keyed by model id.
+var chatCapabilities = map[string][]string{
+	"gemma2-9b-it":                          {"CapabilityTools"},
+	"llama-3.1-70b-versatile":               {"CapabilityTools"},
+	"llama-3.1-8b-instant":                  {"CapabilityTools"},
+	"llama-3.2-11b-vision-preview":          {"CapabilityTools", "CapabilityVision"},
+	"llama-3.2-1b-preview":                  {"CapabilityTools"},
+	"llama-3.2-3b-preview":                  {"CapabilityTools"},
+	"llama-3.2-90b-vision-preview":          {"CapabilityTools", "CapabilityVision"},
+	"llama-3.3-70b-versatile":               {"CapabilityTools"},
+	"llama3-70b-8192":                       {"CapabilityTools"},
+	"llama3-8b-8192":                        {"CapabilityTools"},
+	"llama3-groq-70b-8192-tool-use-preview": {"CapabilityTools"},
+	"llama3-groq-8b-8192-tool-use-preview":  {"CapabilityTools"},
+	"mixtral-8x7b-32768":                    {"CapabilityTools"},
+}
+
 var (
 	// LowerCaseLettersCharset is a set of lower case letters.
 	LowerCaseLettersCharset = []rune("abcdefghijklmnopqrstuvwxyz")
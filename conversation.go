@@ -0,0 +1,391 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+)
+
+const (
+	// defaultContextWindow is the context window assumed for models missing
+	// from the Models registry.
+	defaultContextWindow = 8192
+	// summaryPrompt is the instruction used to summarize older turns of a
+	// conversation.
+	summaryPrompt = "Summarize the conversation so far in a few sentences. " +
+		"Keep every fact, decision and open question needed to continue it."
+	// summaryPrefix prefixes the summary replacing older turns of a
+	// conversation.
+	summaryPrefix = "Summary of the earlier conversation: "
+)
+
+type (
+	// Conversation tracks the messages of a chat and keeps them within the
+	// context window of its model.
+	//
+	// It is safe for concurrent use.
+	Conversation struct {
+		mu            sync.Mutex
+		model         ChatModel
+		contextWindow int
+		replyTokens   int
+		strategy      TruncationStrategy
+		messages      []ChatCompletionMessage
+	}
+	// ConversationOpts is a function that sets options for a Conversation.
+	ConversationOpts func(*Conversation)
+	// TruncationStrategy shortens the messages of a conversation so that
+	// their estimated tokens fit in a budget.
+	TruncationStrategy interface {
+		// Truncate returns the messages shortened to fit in the budget.
+		//
+		// It may return messages exceeding the budget if they can not be
+		// shortened any further.
+		Truncate(
+			ctx context.Context,
+			messages []ChatCompletionMessage,
+			budget int,
+		) ([]ChatCompletionMessage, error)
+	}
+	// TruncationFunc is a function implementing TruncationStrategy.
+	TruncationFunc func(
+		ctx context.Context,
+		messages []ChatCompletionMessage,
+		budget int,
+	) ([]ChatCompletionMessage, error)
+	// conversationJSON is the serialized form of a Conversation.
+	conversationJSON struct {
+		Model         ChatModel               `json:"model"`
+		ContextWindow int                     `json:"context_window"`
+		ReplyTokens   int                     `json:"reply_tokens,omitempty"`
+		Messages      []ChatCompletionMessage `json:"messages"`
+	}
+)
+
+// Truncate implements TruncationStrategy.
+func (f TruncationFunc) Truncate(
+	ctx context.Context,
+	messages []ChatCompletionMessage,
+	budget int,
+) ([]ChatCompletionMessage, error) {
+	return f(ctx, messages, budget)
+}
+
+// NewConversation creates a new conversation with the given model.
+//
+// The context window defaults to the model's in the Models registry. By
+// default older messages are dropped once the conversation outgrows the
+// context window.
+func NewConversation(model ChatModel, opts ...ConversationOpts) *Conversation {
+	c := &Conversation{
+		model:         model,
+		contextWindow: defaultContextWindow,
+		strategy:      DropOldest(),
+	}
+	if info, ok := ModelInfoFor(model); ok {
+		c.contextWindow = info.ContextWindow
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithContextWindow sets the context window of the conversation's model in
+// tokens.
+func WithContextWindow(tokens int) ConversationOpts {
+	return func(c *Conversation) { c.contextWindow = tokens }
+}
+
+// WithReplyTokens sets the number of tokens kept free in the context window
+// for the model's reply.
+//
+// The MaxTokens of a request takes precedence when it is larger.
+func WithReplyTokens(tokens int) ConversationOpts {
+	return func(c *Conversation) { c.replyTokens = tokens }
+}
+
+// WithTruncation sets the truncation strategy of the conversation.
+func WithTruncation(strategy TruncationStrategy) ConversationOpts {
+	return func(c *Conversation) { c.strategy = strategy }
+}
+
+// WithMessages sets the initial messages of the conversation.
+func WithMessages(messages ...ChatCompletionMessage) ConversationOpts {
+	return func(c *Conversation) {
+		c.messages = append([]ChatCompletionMessage(nil), messages...)
+	}
+}
+
+// Model returns the model of the conversation.
+func (c *Conversation) Model() ChatModel {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.model
+}
+
+// ContextWindow returns the context window of the conversation's model.
+func (c *Conversation) ContextWindow() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.contextWindow
+}
+
+// Add appends messages to the conversation.
+func (c *Conversation) Add(messages ...ChatCompletionMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.messages = append(c.messages, messages...)
+}
+
+// Messages returns a copy of the messages of the conversation.
+func (c *Conversation) Messages() []ChatCompletionMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.messages)
+}
+
+// Tokens returns the estimated number of prompt tokens of the
+// conversation.
+func (c *Conversation) Tokens() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return EstimateTokens(c.messages)
+}
+
+// Fit truncates the conversation so that it and a reply of maxTokens fit
+// in the context window.
+//
+// It returns a groqerr.ErrContextWindowExceeded if the truncation strategy
+// can not shorten the conversation enough.
+func (c *Conversation) Fit(ctx context.Context, maxTokens int) error {
+	c.mu.Lock()
+	messages := slices.Clone(c.messages)
+	window := c.contextWindow
+	reserve := max(maxTokens, c.replyTokens)
+	strategy := c.strategy
+	model := c.model
+	c.mu.Unlock()
+	budget := window - reserve
+	if EstimateTokens(messages) <= budget {
+		return nil
+	}
+	truncated, err := strategy.Truncate(ctx, messages, budget)
+	if err != nil {
+		return err
+	}
+	if tokens := EstimateTokens(truncated); tokens > budget {
+		return groqerr.ErrContextWindowExceeded{
+			Model:         string(model),
+			Tokens:        tokens + reserve,
+			ContextWindow: window,
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// keep the messages added while truncating
+	if len(c.messages) >= len(messages) {
+		truncated = append(truncated, c.messages[len(messages):]...)
+	}
+	c.messages = truncated
+	return nil
+}
+
+// ChatCompletion fits the conversation in the context window, sends it
+// with the request and appends the reply to the conversation.
+//
+// The messages of the request are replaced by the conversation's and its
+// model defaults to the conversation's.
+func (c *Conversation) ChatCompletion(
+	ctx context.Context,
+	client *Client,
+	request ChatCompletionRequest,
+) (response ChatCompletionResponse, err error) {
+	err = c.Fit(ctx, request.MaxTokens)
+	if err != nil {
+		return
+	}
+	if request.Model == "" {
+		request.Model = c.Model()
+	}
+	request.Messages = c.Messages()
+	response, err = client.ChatCompletion(ctx, request)
+	if err != nil {
+		return
+	}
+	if len(response.Choices) > 0 {
+		c.Add(response.Choices[0].Message)
+	}
+	return
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+//
+// The truncation strategy is not serialized.
+func (c *Conversation) MarshalJSON() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return json.Marshal(conversationJSON{
+		Model:         c.model,
+		ContextWindow: c.contextWindow,
+		ReplyTokens:   c.replyTokens,
+		Messages:      c.messages,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+//
+// The truncation strategy of the conversation is kept, defaulting to
+// DropOldest.
+func (c *Conversation) UnmarshalJSON(data []byte) error {
+	var v conversationJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.model = v.Model
+	c.contextWindow = v.ContextWindow
+	if c.contextWindow == 0 {
+		c.contextWindow = defaultContextWindow
+		if info, ok := ModelInfoFor(c.model); ok {
+			c.contextWindow = info.ContextWindow
+		}
+	}
+	c.replyTokens = v.ReplyTokens
+	c.messages = v.Messages
+	if c.strategy == nil {
+		c.strategy = DropOldest()
+	}
+	return nil
+}
+
+// DropOldest returns a truncation strategy dropping the oldest messages
+// other than the leading system messages until the conversation fits.
+//
+// Tool results whose tool call was dropped are dropped with it.
+func DropOldest() TruncationStrategy {
+	return TruncationFunc(func(
+		_ context.Context,
+		messages []ChatCompletionMessage,
+		budget int,
+	) ([]ChatCompletionMessage, error) {
+		return dropOldest(messages, budget), nil
+	})
+}
+
+// KeepSystemAndLast returns a truncation strategy keeping the leading
+// system messages and the last n other messages.
+//
+// Older messages are dropped further if the kept ones still do not fit.
+func KeepSystemAndLast(n int) TruncationStrategy {
+	return TruncationFunc(func(
+		_ context.Context,
+		messages []ChatCompletionMessage,
+		budget int,
+	) ([]ChatCompletionMessage, error) {
+		system, rest := splitSystem(messages)
+		if len(rest) > n {
+			rest = trimToolResults(rest[len(rest)-n:])
+		}
+		return dropOldest(append(system, rest...), budget), nil
+	})
+}
+
+// SummarizeOlder returns a truncation strategy replacing the messages older
+// than the last keep messages with a summary written by the model.
+//
+// The summary is added as a system message after the leading system
+// messages. Older messages are dropped further if the summarized
+// conversation still does not fit.
+func SummarizeOlder(client *Client, model ChatModel, keep int) TruncationStrategy {
+	return TruncationFunc(func(
+		ctx context.Context,
+		messages []ChatCompletionMessage,
+		budget int,
+	) ([]ChatCompletionMessage, error) {
+		system, rest := splitSystem(messages)
+		if len(rest) <= keep {
+			return dropOldest(messages, budget), nil
+		}
+		kept := trimToolResults(rest[len(rest)-keep:])
+		// results of the kept tool calls are summarized with their calls
+		older := rest[:len(rest)-len(kept)]
+		summary, err := summarize(ctx, client, model, older)
+		if err != nil {
+			return nil, fmt.Errorf("failed to summarize conversation: %w", err)
+		}
+		summarized := append(system, ChatCompletionMessage{
+			Role:    RoleSystem,
+			Content: summaryPrefix + summary,
+		})
+		return dropOldest(append(summarized, kept...), budget), nil
+	})
+}
+
+// summarize asks the model to summarize the messages.
+func summarize(
+	ctx context.Context,
+	client *Client,
+	model ChatModel,
+	messages []ChatCompletionMessage,
+) (string, error) {
+	response, err := client.ChatCompletion(ctx, ChatCompletionRequest{
+		Model: model,
+		Messages: append(
+			slices.Clip(messages),
+			ChatCompletionMessage{Role: RoleUser, Content: summaryPrompt},
+		),
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("response (%s) has no choices", response.ID)
+	}
+	return strings.TrimSpace(response.Choices[0].Message.Content), nil
+}
+
+// dropOldest drops the oldest messages after the leading system messages
+// until the messages fit in the budget.
+func dropOldest(
+	messages []ChatCompletionMessage,
+	budget int,
+) []ChatCompletionMessage {
+	system, rest := splitSystem(messages)
+	tokens := EstimateTokens(messages)
+	for len(rest) > 0 && tokens > budget {
+		tokens -= EstimateMessageTokens(rest[0])
+		rest = rest[1:]
+		for len(rest) > 0 && rest[0].Role == RoleTool {
+			tokens -= EstimateMessageTokens(rest[0])
+			rest = rest[1:]
+		}
+	}
+	return append(system, rest...)
+}
+
+// splitSystem splits the leading system messages from the rest.
+func splitSystem(
+	messages []ChatCompletionMessage,
+) (system, rest []ChatCompletionMessage) {
+	i := 0
+	for i < len(messages) && messages[i].Role == RoleSystem {
+		i++
+	}
+	return slices.Clone(messages[:i]), messages[i:]
+}
+
+// trimToolResults drops the leading tool results whose tool calls are not
+// part of the messages.
+func trimToolResults(messages []ChatCompletionMessage) []ChatCompletionMessage {
+	for len(messages) > 0 && messages[0].Role == RoleTool {
+		messages = messages[1:]
+	}
+	return messages
+}
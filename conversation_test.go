@@ -0,0 +1,163 @@
+package groq_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"github.com/conneroisu/groq-go/pkg/tools"
+	"github.com/stretchr/testify/assert"
+)
+
+// turns returns n alternating user and assistant messages of 40
+// characters each.
+func turns(n int) []groq.ChatCompletionMessage {
+	messages := make([]groq.ChatCompletionMessage, n)
+	for i := range messages {
+		role := groq.RoleUser
+		if i%2 == 1 {
+			role = groq.RoleAssistant
+		}
+		messages[i] = groq.ChatCompletionMessage{
+			Role:    role,
+			Content: strings.Repeat(string(rune('a'+i)), 40),
+		}
+	}
+	return messages
+}
+
+var systemMessage = groq.ChatCompletionMessage{
+	Role:    groq.RoleSystem,
+	Content: "You are helpful.",
+}
+
+// TestConversationDropOldest tests that the oldest messages are dropped
+// while the system prompt is kept.
+func TestConversationDropOldest(t *testing.T) {
+	a := assert.New(t)
+	// every turn is 14 tokens, the system prompt 8 and the priming 3
+	conv := groq.NewConversation(
+		groq.ModelLlama38B8192,
+		groq.WithContextWindow(60),
+		groq.WithMessages(append([]groq.ChatCompletionMessage{systemMessage}, turns(6)...)...),
+	)
+	a.Equal(3+8+6*14, conv.Tokens())
+	a.NoError(conv.Fit(context.Background(), 0))
+	messages := conv.Messages()
+	a.Equal(systemMessage, messages[0])
+	a.Len(messages, 4)
+	a.Equal(strings.Repeat("d", 40), messages[1].Content)
+	a.LessOrEqual(conv.Tokens(), 60)
+}
+
+// TestConversationReplyTokens tests that room is kept for the reply and
+// that a typed error is returned when the messages can not fit.
+func TestConversationReplyTokens(t *testing.T) {
+	a := assert.New(t)
+	conv := groq.NewConversation(
+		groq.ModelLlama38B8192,
+		groq.WithContextWindow(60),
+		groq.WithReplyTokens(20),
+		groq.WithMessages(append([]groq.ChatCompletionMessage{systemMessage}, turns(4)...)...),
+	)
+	a.NoError(conv.Fit(context.Background(), 0))
+	a.Len(conv.Messages(), 3)
+	// the system prompt alone does not leave room for the reply
+	err := conv.Fit(context.Background(), 55)
+	var windowErr groqerr.ErrContextWindowExceeded
+	a.ErrorAs(err, &windowErr)
+	a.Equal(3+8+55, windowErr.Tokens)
+	a.Equal(60, windowErr.ContextWindow)
+	a.Equal(string(groq.ModelLlama38B8192), windowErr.Model)
+}
+
+// TestConversationKeepSystemAndLast tests keeping the last messages and
+// dropping tool results whose calls were dropped.
+func TestConversationKeepSystemAndLast(t *testing.T) {
+	a := assert.New(t)
+	messages := []groq.ChatCompletionMessage{
+		systemMessage,
+		{Role: groq.RoleUser, Content: "What time is it?"},
+		{Role: groq.RoleAssistant, ToolCalls: []tools.ToolCall{{ID: "call_1"}}},
+		{Role: groq.RoleTool, ToolCallID: "call_1", Content: "noon"},
+		{Role: groq.RoleAssistant, Content: "It is noon."},
+		{Role: groq.RoleUser, Content: "Thanks!"},
+	}
+	truncated, err := groq.KeepSystemAndLast(3).
+		Truncate(context.Background(), messages, 1000)
+	a.NoError(err)
+	a.Equal([]groq.ChatCompletionMessage{
+		systemMessage,
+		{Role: groq.RoleAssistant, Content: "It is noon."},
+		{Role: groq.RoleUser, Content: "Thanks!"},
+	}, truncated)
+}
+
+// TestConversationSummarize tests that older turns are replaced by a
+// summary written by the model.
+func TestConversationSummarize(t *testing.T) {
+	a := assert.New(t)
+	client, requests, teardown := setupAgentTestServer(t,
+		jsonReply("They talked about letters."),
+		jsonReply("Sure."),
+	)
+	defer teardown()
+	conv := groq.NewConversation(
+		groq.ModelLlama38B8192,
+		groq.WithContextWindow(60),
+		groq.WithTruncation(groq.SummarizeOlder(client, groq.ModelLlama38B8192, 2)),
+		groq.WithMessages(append([]groq.ChatCompletionMessage{systemMessage}, turns(5)...)...),
+	)
+	response, err := conv.ChatCompletion(
+		context.Background(),
+		client,
+		groq.ChatCompletionRequest{},
+	)
+	a.NoError(err)
+	a.Equal("Sure.", response.Choices[0].Message.Content)
+	a.Len(*requests, 2)
+	summaryRequest := (*requests)[0].Messages
+	a.Len(summaryRequest, 4)
+	a.Equal(groq.RoleUser, summaryRequest[3].Role)
+	sent := (*requests)[1]
+	a.Equal(groq.ModelLlama38B8192, sent.Model)
+	a.Len(sent.Messages, 4)
+	a.Equal(groq.RoleSystem, sent.Messages[1].Role)
+	a.Contains(sent.Messages[1].Content, "They talked about letters.")
+	messages := conv.Messages()
+	a.Len(messages, 5)
+	a.Equal("Sure.", messages[4].Content)
+}
+
+// TestConversationJSON tests that a conversation survives a JSON round
+// trip.
+func TestConversationJSON(t *testing.T) {
+	a := assert.New(t)
+	conv := groq.NewConversation(
+		groq.ModelLlama38B8192,
+		groq.WithContextWindow(1000),
+		groq.WithReplyTokens(100),
+		groq.WithMessages(systemMessage, turns(2)[0]),
+	)
+	b, err := json.Marshal(conv)
+	a.NoError(err)
+	restored := groq.NewConversation("")
+	a.NoError(json.Unmarshal(b, restored))
+	a.Equal(groq.ModelLlama38B8192, restored.Model())
+	a.Equal(1000, restored.ContextWindow())
+	a.Equal(conv.Messages(), restored.Messages())
+	b2, err := json.Marshal(restored)
+	a.NoError(err)
+	a.JSONEq(string(b), string(b2))
+}
+
+// TestConversationRegistryWindow tests that the context window defaults to
+// the model's in the registry.
+func TestConversationRegistryWindow(t *testing.T) {
+	a := assert.New(t)
+	a.Equal(131072, groq.NewConversation(groq.ModelLlama318BInstant).ContextWindow())
+	a.Equal(8192, groq.NewConversation("not-a-model").ContextWindow())
+}
@@ -14,7 +14,7 @@ import (
 )
 
 var (
-	history = []groq.ChatCompletionMessage{}
+	history = groq.NewConversation(groq.ModelGemma29BIt)
 )
 
 func main() {
@@ -71,15 +71,19 @@ func input(
 		lines = append(lines, line)
 		break
 	}
-	history = append(history, groq.ChatCompletionMessage{
+	history.Add(groq.ChatCompletionMessage{
 		Role:    groq.RoleUser,
 		Content: strings.Join(lines, "\n"),
 	})
+	err := history.Fit(ctx, 2000)
+	if err != nil {
+		return err
+	}
 	output, err := client.ChatCompletionStream(
 		ctx,
 		groq.ChatCompletionRequest{
-			Model:     groq.ModelGemma29BIt,
-			Messages:  history,
+			Model:     history.Model(),
+			Messages:  history.Messages(),
 			MaxTokens: 2000,
 		},
 	)
@@ -87,15 +91,20 @@ func input(
 		return err
 	}
 	fmt.Fprintln(writer, "\nai: ")
-	for {
-		response, err := output.Recv()
+	var reply strings.Builder
+	for response, err := range output.All() {
 		if err != nil {
 			return err
 		}
-		if response.Choices[0].FinishReason == groq.ReasonStop {
-			break
+		if len(response.Choices) == 0 {
+			continue
 		}
+		reply.WriteString(response.Choices[0].Delta.Content)
 		fmt.Fprint(writer, response.Choices[0].Delta.Content)
 	}
+	history.Add(groq.ChatCompletionMessage{
+		Role:    groq.RoleAssistant,
+		Content: reply.String(),
+	})
 	return nil
 }
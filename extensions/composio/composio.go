@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/conneroisu/groq-go"
 	"github.com/conneroisu/groq-go/pkg/builders"
 )
 
@@ -22,6 +23,8 @@ type (
 		logger  *slog.Logger
 		header  builders.Header
 		baseURL string
+
+		middleware []groq.Middleware
 	}
 	// Integration represents a composio integration.
 	Integration struct {
@@ -47,7 +50,19 @@ func NewComposer(apiKey string, opts ...Option) (*Composio, error) {
 	return c, nil
 }
 
+// doRequest passes the request through the middleware of the client before
+// sending it.
 func (c *Composio) doRequest(req *http.Request, v interface{}) error {
+	return groq.InvokeRequest(
+		c.middleware,
+		"composio "+req.Method+" "+req.URL.Path,
+		req,
+		v,
+		c.send,
+	)
+}
+
+func (c *Composio) send(req *http.Request, v interface{}) error {
 	req.Header.Set("Accept", "application/json")
 	contentType := req.Header.Get("Content-Type")
 	if contentType == "" {
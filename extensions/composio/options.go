@@ -5,6 +5,8 @@ import (
 	"log/slog"
 	"net/url"
 	"strings"
+
+	"github.com/conneroisu/groq-go"
 )
 
 type (
@@ -32,6 +34,14 @@ func WithBaseURL(baseURL string) Option {
 	return func(c *Composio) { c.baseURL = baseURL }
 }
 
+// WithMiddleware appends middleware to the chain wrapping every request of
+// the composio client.
+func WithMiddleware(middleware ...groq.Middleware) Option {
+	return func(c *Composio) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
 // Get Tool Options
 
 // WithTags sets the tags for the tools request.
@@ -8,6 +8,7 @@ import (
 
 	"github.com/conneroisu/groq-go"
 	"github.com/conneroisu/groq-go/pkg/builders"
+	"github.com/conneroisu/groq-go/pkg/tools"
 )
 
 type (
@@ -26,6 +27,12 @@ type (
 		Text               string         `json:"text,omitempty"`
 		AuthConfig         map[string]any `json:"authConfig,omitempty"`
 	}
+	// toolProvider runs composio tools for a connected account.
+	toolProvider struct {
+		composio *Composio
+		user     ConnectedAccount
+		opts     []ToolsOption
+	}
 )
 
 // Run runs the composio client on a chat completion response.
@@ -40,40 +47,75 @@ func (c *Composio) Run(
 		return nil, fmt.Errorf("not a function call")
 	}
 	for _, toolCall := range response.Choices[0].Message.ToolCalls {
-		var args map[string]any
-		if json.Valid([]byte(toolCall.Function.Arguments)) {
-			err := json.Unmarshal([]byte(toolCall.Function.Arguments), &args)
-			if err != nil {
-				return nil, err
-			}
-			c.logger.Debug("arguments", "args", args)
-		}
-		req, err := builders.NewRequest(
-			ctx,
-			c.header,
-			http.MethodPost,
-			fmt.Sprintf("%s/v2/actions/%s/execute", c.baseURL, toolCall.Function.Name),
-			builders.WithBody(&request{
-				ConnectedAccountID: user.ID,
-				EntityID:           "default",
-				AppName:            toolCall.Function.Name,
-				Input:              args,
-				AuthConfig:         map[string]any{},
-			}),
-		)
+		msg, err := c.runTool(ctx, user, toolCall)
 		if err != nil {
 			return nil, err
 		}
-		var body string
-		err = c.doRequest(req, &body)
+		respH = append(respH, msg)
+	}
+	return respH, nil
+}
+
+// ToolProvider returns a groq.ToolProvider running the composio tools
+// selected by the options on behalf of the given connected account.
+func (c *Composio) ToolProvider(
+	user ConnectedAccount,
+	opts ...ToolsOption,
+) groq.ToolProvider {
+	return &toolProvider{composio: c, user: user, opts: opts}
+}
+
+// Tools returns the composio tools of the provider.
+func (p *toolProvider) Tools(ctx context.Context) ([]tools.Tool, error) {
+	return p.composio.GetTools(ctx, p.opts...)
+}
+
+// RunTool runs a single composio tool call.
+func (p *toolProvider) RunTool(
+	ctx context.Context,
+	toolCall tools.ToolCall,
+) (groq.ChatCompletionMessage, error) {
+	return p.composio.runTool(ctx, p.user, toolCall)
+}
+
+// runTool executes a single tool call for the connected account.
+func (c *Composio) runTool(
+	ctx context.Context,
+	user ConnectedAccount,
+	toolCall tools.ToolCall,
+) (groq.ChatCompletionMessage, error) {
+	var args map[string]any
+	if json.Valid([]byte(toolCall.Function.Arguments)) {
+		err := json.Unmarshal([]byte(toolCall.Function.Arguments), &args)
 		if err != nil {
-			return nil, err
+			return groq.ChatCompletionMessage{}, err
 		}
-		respH = append(respH, groq.ChatCompletionMessage{
-			Content: body,
-			Name:    toolCall.ID,
-			Role:    groq.RoleFunction,
-		})
+		c.logger.Debug("arguments", "args", args)
 	}
-	return respH, nil
+	req, err := builders.NewRequest(
+		ctx,
+		c.header,
+		http.MethodPost,
+		fmt.Sprintf("%s/v2/actions/%s/execute", c.baseURL, toolCall.Function.Name),
+		builders.WithBody(&request{
+			ConnectedAccountID: user.ID,
+			EntityID:           "default",
+			AppName:            toolCall.Function.Name,
+			Input:              args,
+			AuthConfig:         map[string]any{},
+		}),
+	)
+	if err != nil {
+		return groq.ChatCompletionMessage{}, err
+	}
+	var body string
+	err = c.doRequest(req, &body)
+	if err != nil {
+		return groq.ChatCompletionMessage{}, err
+	}
+	return groq.ChatCompletionMessage{
+		Content: body,
+		Name:    toolCall.ID,
+		Role:    groq.RoleFunction,
+	}, nil
 }
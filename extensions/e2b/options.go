@@ -3,6 +3,8 @@ package e2b
 import (
 	"log/slog"
 	"net/http"
+
+	"github.com/conneroisu/groq-go"
 )
 
 // E2B Sandbox Options
@@ -44,6 +46,14 @@ func WithWsURL(wsURL func(s *Sandbox) string) Option {
 	return func(s *Sandbox) { s.wsURL = wsURL }
 }
 
+// WithMiddleware appends middleware to the chain wrapping every http
+// request of the e2b sandbox.
+func WithMiddleware(middleware ...groq.Middleware) Option {
+	return func(s *Sandbox) {
+		s.middleware = append(s.middleware, middleware...)
+	}
+}
+
 // Process Options
 
 // ProcessWithEnv sets the environment variables for the process.
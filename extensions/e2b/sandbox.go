@@ -12,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/conneroisu/groq-go"
 	"github.com/conneroisu/groq-go/pkg/builders"
 	"github.com/gorilla/websocket"
 )
@@ -41,6 +42,8 @@ type (
 		Map      *sync.Map               `json:"-"`          // Map is the map of the sandbox.
 		idCh     chan int                `json:"-"`          // idCh is the channel to generate ids for requests.
 		toolW    ToolingWrapper          `json:"-"`          // toolW is the tooling wrapper for the sandbox.
+
+		middleware []groq.Middleware // middleware wraps the http requests of the sandbox.
 	}
 	// Option is an option for the sandbox.
 	Option func(*Sandbox)
@@ -199,16 +202,18 @@ func (s *Sandbox) KeepAlive(ctx context.Context, timeout time.Duration) error {
 	if err != nil {
 		return err
 	}
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode < http.StatusOK ||
-		resp.StatusCode >= http.StatusBadRequest {
-		return fmt.Errorf("request to create sandbox failed: %s", resp.Status)
-	}
-	return nil
+	return s.invoke(req, nil, func(req *http.Request, _ any) error {
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < http.StatusOK ||
+			resp.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("request to create sandbox failed: %s", resp.Status)
+		}
+		return nil
+	})
 }
 
 // Reconnect reconnects to the sandbox.
@@ -240,16 +245,18 @@ func (s *Sandbox) Stop(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode < http.StatusOK ||
-		resp.StatusCode >= http.StatusBadRequest {
-		return fmt.Errorf("request to delete sandbox failed: %s", resp.Status)
-	}
-	return nil
+	return s.invoke(req, nil, func(req *http.Request, _ any) error {
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < http.StatusOK ||
+			resp.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("request to delete sandbox failed: %s", resp.Status)
+		}
+		return nil
+	})
 }
 
 // Mkdir makes a directory in the sandbox file system.
@@ -543,7 +550,30 @@ func (p *Process) subscribe(
 	}(errs)
 	return events, errs
 }
+
+// invoke passes the request through the middleware of the sandbox before
+// sending it with send.
+func (s *Sandbox) invoke(
+	req *http.Request,
+	v any,
+	send func(req *http.Request, v any) error,
+) error {
+	return groq.InvokeRequest(
+		s.middleware,
+		"e2b "+req.Method+" "+req.URL.Path,
+		req,
+		v,
+		send,
+	)
+}
+
+// sendRequest passes the request through the middleware of the sandbox
+// before sending it.
 func (s *Sandbox) sendRequest(req *http.Request, v interface{}) error {
+	return s.invoke(req, v, s.send)
+}
+
+func (s *Sandbox) send(req *http.Request, v interface{}) error {
 	res, err := s.client.Do(req)
 	if err != nil {
 		return err
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/conneroisu/groq-go"
+	"github.com/conneroisu/groq-go/pkg/groqerr"
 	"github.com/conneroisu/groq-go/pkg/tools"
 )
 
@@ -291,6 +292,32 @@ func (s *Sandbox) RunTooling(
 	return respH, nil
 }
 
+// Tools returns the tools of the sandbox.
+//
+// It implements the groq.ToolProvider interface.
+func (s *Sandbox) Tools(context.Context) ([]tools.Tool, error) {
+	return s.GetTools(), nil
+}
+
+// RunTool runs a single tool call in the sandbox.
+//
+// It implements the groq.ToolProvider interface.
+func (s *Sandbox) RunTool(
+	ctx context.Context,
+	call tools.ToolCall,
+) (groq.ChatCompletionMessage, error) {
+	for _, t := range s.toolW.getTools() {
+		if t.Function.Name == call.Function.Name {
+			return s.runTool(ctx, t, call)
+		}
+	}
+	return groq.ChatCompletionMessage{}, groqerr.ErrToolNotFound{
+		ToolName: call.Function.Name,
+	}
+}
+
+var _ groq.ToolProvider = (*Sandbox)(nil)
+
 func (s *Sandbox) runTool(
 	ctx context.Context,
 	tool tools.Tool,
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/conneroisu/groq-go"
 	"github.com/conneroisu/groq-go/pkg/builders"
 )
 
@@ -21,6 +22,8 @@ type (
 		client  *http.Client
 		logger  *slog.Logger
 		header  builders.Header
+
+		middleware []groq.Middleware
 	}
 	// Option is an option for the JigsawStack client.
 	Option func(*JigsawStack)
@@ -61,7 +64,27 @@ func WithLogger(logger *slog.Logger) Option {
 	return func(j *JigsawStack) { j.logger = logger }
 }
 
+// WithMiddleware appends middleware to the chain wrapping every request of
+// the JigsawStack extension.
+func WithMiddleware(middleware ...groq.Middleware) Option {
+	return func(j *JigsawStack) {
+		j.middleware = append(j.middleware, middleware...)
+	}
+}
+
+// sendRequest passes the request through the middleware of the extension
+// before sending it.
 func (j *JigsawStack) sendRequest(req *http.Request, v any) error {
+	return groq.InvokeRequest(
+		j.middleware,
+		"jigsawstack "+req.Method+" "+req.URL.Path,
+		req,
+		v,
+		j.send,
+	)
+}
+
+func (j *JigsawStack) send(req *http.Request, v any) error {
 	j.header.SetCommonHeaders(req)
 	resp, err := j.client.Do(req)
 	if err != nil {
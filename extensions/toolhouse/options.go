@@ -3,6 +3,8 @@ package toolhouse
 import (
 	"log/slog"
 	"net/http"
+
+	"github.com/conneroisu/groq-go"
 )
 
 // WithBaseURL sets the base URL for the Toolhouse extension.
@@ -24,3 +26,11 @@ func WithMetadata(metadata map[string]any) Options {
 func WithLogger(logger *slog.Logger) Options {
 	return func(r *Toolhouse) { r.logger = logger }
 }
+
+// WithMiddleware appends middleware to the chain wrapping every request of
+// the Toolhouse extension.
+func WithMiddleware(middleware ...groq.Middleware) Options {
+	return func(e *Toolhouse) {
+		e.middleware = append(e.middleware, middleware...)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/conneroisu/groq-go"
 	"github.com/conneroisu/groq-go/pkg/builders"
 )
 
@@ -29,6 +30,8 @@ type (
 		metadata map[string]any
 		logger   *slog.Logger
 		header   builders.Header
+
+		middleware []groq.Middleware
 	}
 
 	// Options is a function that sets options for a Toolhouse extension.
@@ -60,7 +63,19 @@ func NewExtension(apiKey string, opts ...Options) (e *Toolhouse, err error) {
 	return e, nil
 }
 
+// sendRequest passes the request through the middleware of the extension
+// before sending it.
 func (e *Toolhouse) sendRequest(req *http.Request, v interface{}) error {
+	return groq.InvokeRequest(
+		e.middleware,
+		"toolhouse "+req.Method+" "+req.URL.Path,
+		req,
+		v,
+		e.send,
+	)
+}
+
+func (e *Toolhouse) send(req *http.Request, v interface{}) error {
 	req.Header.Set("Accept", "application/json")
 	contentType := req.Header.Get("Content-Type")
 	if contentType == "" {
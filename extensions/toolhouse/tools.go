@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/conneroisu/groq-go"
 	"github.com/conneroisu/groq-go/pkg/builders"
 	"github.com/conneroisu/groq-go/pkg/tools"
 )
@@ -36,3 +37,12 @@ func (e *Toolhouse) GetTools(
 	}
 	return tooling, nil
 }
+
+// Tools returns the tools of the extension.
+//
+// It implements the groq.ToolProvider interface.
+func (e *Toolhouse) Tools(ctx context.Context) ([]tools.Tool, error) {
+	return e.GetTools(ctx)
+}
+
+var _ groq.ToolProvider = (*Toolhouse)(nil)
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/conneroisu/groq-go"
 	"github.com/conneroisu/groq-go/extensions/toolhouse"
 	"github.com/conneroisu/groq-go/internal/test"
 	"github.com/conneroisu/groq-go/pkg/tools"
@@ -50,3 +51,37 @@ func TestGetTools(t *testing.T) {
 	a.NoError(err)
 	a.NotEmpty(tools)
 }
+
+// TestGetToolsMiddleware tests that the requests of the extension pass
+// through its middleware.
+func TestGetToolsMiddleware(t *testing.T) {
+	a := assert.New(t)
+	ts := test.NewTestServer()
+	ts.RegisterHandler("/get_tools", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode([]tools.Tool{{
+			Type:     tools.ToolTypeFunction,
+			Function: tools.FunctionDefinition{Name: "tool"},
+		}})
+	})
+	testS := ts.ToolhouseTestServer()
+	testS.Start()
+	defer testS.Close()
+	var operations []string
+	client, err := toolhouse.NewExtension(
+		test.GetTestToken(),
+		toolhouse.WithBaseURL(testS.URL),
+		toolhouse.WithClient(testS.Client()),
+		toolhouse.WithMiddleware(func(next groq.Handler) groq.Handler {
+			return func(ctx context.Context, call *groq.Call) (any, error) {
+				operations = append(operations, call.Operation)
+				a.IsType(&http.Request{}, call.Request)
+				return next(ctx, call)
+			}
+		}),
+	)
+	a.NoError(err)
+	tools, err := client.GetTools(context.Background())
+	a.NoError(err)
+	a.Len(tools, 1)
+	a.Equal([]string{"toolhouse POST /get_tools"}, operations)
+}
@@ -0,0 +1,156 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+)
+
+type (
+	// FallbackPolicy configures which errors make the client retry a chat
+	// completion against the next model of a fallback chain.
+	//
+	// Errors of other classes, such as invalid requests, are returned
+	// without trying the fallback models.
+	FallbackPolicy struct {
+		// OnRateLimit falls back when the model is rate limited, either by
+		// a 429 response or by the client side rate limiter.
+		OnRateLimit bool
+		// OnServerError falls back when the api answers with a 5xx status
+		// code after the retries of the RetryPolicy.
+		OnServerError bool
+		// OnModelNotFound falls back when the model does not exist or was
+		// decommissioned.
+		OnModelNotFound bool
+		// OnContextLengthExceeded falls back when the request does not fit
+		// in the context window of the model.
+		OnContextLengthExceeded bool
+	}
+	// fallbackClass is the class of an error deciding whether a fallback
+	// model is tried.
+	fallbackClass int
+)
+
+const (
+	fallbackNone fallbackClass = iota
+	fallbackRateLimit
+	fallbackServerError
+	fallbackModelNotFound
+	fallbackContextLength
+)
+
+// DefaultFallbackPolicy returns the fallback policy used by a client when
+// none is configured, which falls back on every error class.
+func DefaultFallbackPolicy() FallbackPolicy {
+	return FallbackPolicy{
+		OnRateLimit:             true,
+		OnServerError:           true,
+		OnModelNotFound:         true,
+		OnContextLengthExceeded: true,
+	}
+}
+
+// WithFallback sets the models a chat completion requested with the
+// primary model is retried against, in order, when it fails.
+//
+// Requests for other models are not affected. The model that answered is
+// recorded in the AnsweredBy field of the response.
+func WithFallback(primary ChatModel, fallbacks ...ChatModel) Opts {
+	return func(c *Client) {
+		if c.fallbacks == nil {
+			c.fallbacks = make(map[ChatModel][]ChatModel)
+		}
+		c.fallbacks[primary] = append([]ChatModel(nil), fallbacks...)
+	}
+}
+
+// WithFallbackPolicy sets the fallback policy for the Groq client.
+func WithFallbackPolicy(policy FallbackPolicy) Opts {
+	return func(c *Client) { c.fallbackPolicy = policy }
+}
+
+// withFallback calls send with the model and then with its fallback models
+// until it succeeds or fails with an error the policy does not fall back
+// on.
+//
+// The error of the last model tried is returned.
+func (c *Client) withFallback(
+	ctx context.Context,
+	model ChatModel,
+	send func(model ChatModel) error,
+) error {
+	models := append([]ChatModel{model}, c.fallbacks[model]...)
+	var err error
+	for i, m := range models {
+		err = send(m)
+		if err == nil || i == len(models)-1 || ctx.Err() != nil ||
+			!c.fallbackPolicy.allows(classifyFallback(err)) {
+			return err
+		}
+		c.logger.Debug(
+			"falling back to next model",
+			"model", m,
+			"fallback", models[i+1],
+			"error", err,
+		)
+	}
+	return err
+}
+
+// allows reports whether the policy falls back on errors of the class.
+func (p FallbackPolicy) allows(class fallbackClass) bool {
+	switch class {
+	case fallbackRateLimit:
+		return p.OnRateLimit
+	case fallbackServerError:
+		return p.OnServerError
+	case fallbackModelNotFound:
+		return p.OnModelNotFound
+	case fallbackContextLength:
+		return p.OnContextLengthExceeded
+	default:
+		return false
+	}
+}
+
+// classifyFallback returns the fallback class of an error.
+func classifyFallback(err error) fallbackClass {
+	var (
+		rateLimited groqerr.ErrRateLimited
+		windowErr   groqerr.ErrContextWindowExceeded
+		apiErr      *groqerr.APIError
+		reqErr      *groqerr.ErrRequest
+	)
+	switch {
+	case errors.As(err, &rateLimited):
+		return fallbackRateLimit
+	case errors.As(err, &windowErr):
+		return fallbackContextLength
+	case errors.As(err, &apiErr):
+		switch apiErr.Code {
+		case "model_not_found", "model_decommissioned":
+			return fallbackModelNotFound
+		case "context_length_exceeded":
+			return fallbackContextLength
+		}
+		return classifyStatus(apiErr.HTTPStatusCode)
+	case errors.As(err, &reqErr):
+		return classifyStatus(reqErr.HTTPStatusCode)
+	default:
+		return fallbackNone
+	}
+}
+
+// classifyStatus returns the fallback class of an http status code.
+func classifyStatus(code int) fallbackClass {
+	switch {
+	case code == http.StatusTooManyRequests:
+		return fallbackRateLimit
+	case code >= http.StatusInternalServerError:
+		return fallbackServerError
+	default:
+		return fallbackNone
+	}
+}
@@ -0,0 +1,153 @@
+package groq_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"github.com/stretchr/testify/assert"
+)
+
+// modelFailures maps the models of the fallback test server to the status
+// code and error code they fail with.
+var modelFailures = map[groq.ChatModel]struct {
+	status int
+	code   string
+}{
+	groq.ModelLlama3370BVersatile: {http.StatusServiceUnavailable, ""},
+	groq.ModelGemma7BIt:           {http.StatusNotFound, "model_not_found"},
+	groq.ModelLlama38B8192:        {http.StatusBadRequest, "context_length_exceeded"},
+	groq.ModelLlama370B8192:       {http.StatusTooManyRequests, "rate_limit_exceeded"},
+	groq.ModelMixtral8X7B32768:    {http.StatusBadRequest, "invalid_request_error"},
+}
+
+// setupFallbackTestServer sets up a test server failing the requests for
+// the models of modelFailures and returns the models requested.
+func setupFallbackTestServer(
+	t *testing.T,
+	opts ...groq.Opts,
+) (*groq.Client, *[]groq.ChatModel, func()) {
+	t.Helper()
+	client, server, teardown := setupGroqTestServer(
+		append([]groq.Opts{groq.WithRetryPolicy(groq.NoRetryPolicy())}, opts...)...,
+	)
+	var (
+		mu     sync.Mutex
+		models []groq.ChatModel
+	)
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		func(w http.ResponseWriter, r *http.Request) {
+			var req groq.ChatCompletionRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			mu.Lock()
+			models = append(models, req.Model)
+			mu.Unlock()
+			if failure, ok := modelFailures[req.Model]; ok {
+				w.WriteHeader(failure.status)
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"error": map[string]any{
+						"message": "failed",
+						"type":    "invalid_request_error",
+						"code":    failure.code,
+					},
+				})
+				return
+			}
+			response := jsonReply("hello")
+			response.Model = req.Model
+			_ = json.NewEncoder(w).Encode(response)
+		},
+	)
+	return client, &models, teardown
+}
+
+// TestFallbackChain tests that failing models fall back in order and that
+// the answering model is recorded.
+func TestFallbackChain(t *testing.T) {
+	a := assert.New(t)
+	client, models, teardown := setupFallbackTestServer(t,
+		groq.WithFallback(
+			groq.ModelLlama3370BVersatile,
+			groq.ModelGemma7BIt,
+			groq.ModelLlama38B8192,
+			groq.ModelLlama370B8192,
+			groq.ModelLlama318BInstant,
+		),
+	)
+	defer teardown()
+	response, err := client.ChatCompletion(context.Background(), groq.ChatCompletionRequest{
+		Model:    groq.ModelLlama3370BVersatile,
+		Messages: []groq.ChatCompletionMessage{{Role: groq.RoleUser, Content: "hi"}},
+	})
+	a.NoError(err)
+	a.Equal(groq.ModelLlama318BInstant, response.AnsweredBy)
+	a.Equal([]groq.ChatModel{
+		groq.ModelLlama3370BVersatile,
+		groq.ModelGemma7BIt,
+		groq.ModelLlama38B8192,
+		groq.ModelLlama370B8192,
+		groq.ModelLlama318BInstant,
+	}, *models)
+
+	stream, err := client.ChatCompletionStream(context.Background(), groq.ChatCompletionRequest{
+		Model:    groq.ModelLlama3370BVersatile,
+		Messages: []groq.ChatCompletionMessage{{Role: groq.RoleUser, Content: "hi"}},
+	})
+	a.NoError(err)
+	a.Equal(groq.ModelLlama318BInstant, stream.AnsweredBy)
+	stream.Close()
+}
+
+// TestFallbackPolicy tests that only the error classes enabled by the
+// policy fall back.
+func TestFallbackPolicy(t *testing.T) {
+	a := assert.New(t)
+	client, models, teardown := setupFallbackTestServer(t,
+		groq.WithFallback(groq.ModelLlama3370BVersatile, groq.ModelLlama318BInstant),
+		groq.WithFallback(groq.ModelMixtral8X7B32768, groq.ModelLlama318BInstant),
+		groq.WithFallback(groq.ModelLlama38B8192, groq.ModelLlama318BInstant),
+		groq.WithFallbackPolicy(groq.FallbackPolicy{OnContextLengthExceeded: true}),
+	)
+	defer teardown()
+	request := groq.ChatCompletionRequest{
+		Messages: []groq.ChatCompletionMessage{{Role: groq.RoleUser, Content: "hi"}},
+	}
+
+	request.Model = groq.ModelLlama3370BVersatile
+	_, err := client.ChatCompletion(context.Background(), request)
+	var apiErr *groqerr.APIError
+	a.ErrorAs(err, &apiErr)
+	a.Equal(http.StatusServiceUnavailable, apiErr.HTTPStatusCode)
+
+	// invalid requests never fall back
+	request.Model = groq.ModelMixtral8X7B32768
+	_, err = client.ChatCompletion(context.Background(), request)
+	a.Error(err)
+
+	request.Model = groq.ModelLlama38B8192
+	response, err := client.ChatCompletion(context.Background(), request)
+	a.NoError(err)
+	a.Equal(groq.ModelLlama318BInstant, response.AnsweredBy)
+
+	// the context window of the registry is checked before sending
+	request.MaxTokens = 20000
+	response, err = client.ChatCompletion(context.Background(), request)
+	a.NoError(err)
+	a.Equal(groq.ModelLlama318BInstant, response.AnsweredBy)
+
+	a.Equal([]groq.ChatModel{
+		groq.ModelLlama3370BVersatile,
+		groq.ModelMixtral8X7B32768,
+		groq.ModelLlama38B8192,
+		groq.ModelLlama318BInstant,
+		groq.ModelLlama318BInstant,
+	}, *models)
+}
@@ -1,6 +1,7 @@
 package groq
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -25,13 +26,20 @@ type (
 		baseURL            string
 		emptyMessagesLimit uint
 
-		header             builders.Header
-		requestFormBuilder builders.FormBuilder
+		header builders.Header
 
-		client *http.Client
-		logger *slog.Logger
+		client      *http.Client
+		logger      *slog.Logger
+		retryPolicy RetryPolicy
+		rateLimiter *RateLimiter
+
+		fallbacks      map[ChatModel][]ChatModel
+		fallbackPolicy FallbackPolicy
+		middleware     []Middleware
 
 		// TaskCompletionEndpoint is the endpoint for task completion.
+		//
+		// It is relative to the base url, which already ends in /v1.
 		TaskCompletionEndpoint string
 	}
 	// Opts is a function that sets options for a Groq client.
@@ -59,12 +67,14 @@ func NewClient(groqAPIKey string, opts ...Opts) (*Client, error) {
 		return nil, fmt.Errorf("groq api key is required")
 	}
 	c := &Client{
-		groqAPIKey:         groqAPIKey,
-		client:             http.DefaultClient,
-		logger:             slog.Default(),
-		baseURL:            groqAPIURLv1,
-		emptyMessagesLimit: 10,
-		TaskCompletionEndpoint: "/v1/task/completion",
+		groqAPIKey:             groqAPIKey,
+		client:                 http.DefaultClient,
+		logger:                 slog.Default(),
+		baseURL:                groqAPIURLv1,
+		emptyMessagesLimit:     10,
+		retryPolicy:            DefaultRetryPolicy(),
+		fallbackPolicy:         DefaultFallbackPolicy(),
+		TaskCompletionEndpoint: "/task/completion",
 	}
 	for _, opt := range opts {
 		opt(c)
@@ -80,8 +90,23 @@ func NewClient(groqAPIKey string, opts ...Opts) (*Client, error) {
 
 // SignifyTaskCompletion signifies task completion by an LLM.
 func (c *Client) SignifyTaskCompletion(taskID string) error {
-	req, err := builders.NewRequest(
+	_, err := invoke(
 		context.Background(),
+		c,
+		"SignifyTaskCompletion",
+		"",
+		taskID,
+		func(ctx context.Context, taskID string) (struct{}, error) {
+			return struct{}{}, c.signifyTaskCompletion(ctx, taskID)
+		},
+	)
+	return err
+}
+
+// signifyTaskCompletion sends the task completion request.
+func (c *Client) signifyTaskCompletion(ctx context.Context, taskID string) error {
+	req, err := builders.NewRequest(
+		ctx,
 		c.header,
 		http.MethodPost,
 		c.baseURL+c.TaskCompletionEndpoint,
@@ -112,6 +137,14 @@ func (c *Client) fullURL(suffix endpoint, setters ...fullURLOption) string {
 }
 
 func (c *Client) sendRequest(req *http.Request, v response) error {
+	return c.sendRequestRetry(req, v, c.retryPolicy)
+}
+
+func (c *Client) sendRequestRetry(
+	req *http.Request,
+	v response,
+	policy RetryPolicy,
+) error {
 	req.Header.Set("Accept", "application/json")
 	// Check whether Content-Type is already set, Upload Files API requires
 	// Content-Type == multipart/form-data
@@ -119,7 +152,7 @@ func (c *Client) sendRequest(req *http.Request, v response) error {
 	if contentType == "" {
 		req.Header.Set("Content-Type", "application/json")
 	}
-	res, err := c.client.Do(req)
+	res, err := c.do(req, policy)
 	if err != nil {
 		return err
 	}
@@ -158,8 +191,9 @@ func sendRequestStream[T streams.Streamer[ChatCompletionStreamResponse]](
 	req.Header.Set("Accept", "text/event-stream")
 	req.Header.Set("Cache-Control", "no-cache")
 	req.Header.Set("Connection", "keep-alive")
-	resp, err := client.client.Do(
+	resp, err := client.do(
 		req,
+		client.retryPolicy,
 	) //nolint:bodyclose // body is closed in stream.Close()
 	if err != nil {
 		return new(streams.StreamReader[*ChatCompletionStreamResponse]), err
@@ -204,7 +238,7 @@ func decodeString(body io.Reader, output *string) error {
 }
 
 func withModel[
-	T ChatModel | AudioModel | ModerationModel,
+	T ChatModel | AudioModel | ModerationModel | EmbeddingModel | SpeechModel,
 ](model T) fullURLOption {
 	return func(args *fullURLOptions) {
 		args.model = string(model)
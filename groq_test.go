@@ -3,6 +3,7 @@ package groq_test
 import (
 	"log/slog"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	groq "github.com/conneroisu/groq-go"
@@ -21,3 +22,19 @@ func TestClient(t *testing.T) {
 	a.NoError(err)
 	a.NotNil(client)
 }
+
+// TestTaskCompletionURL tests that the task completion endpoint is resolved
+// against the /v1 base url without repeating its version.
+func TestTaskCompletionURL(t *testing.T) {
+	a := assert.New(t)
+	var path string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+	client, err := groq.NewClient("test", groq.WithBaseURL(ts.URL+"/v1"))
+	a.NoError(err)
+	a.NoError(client.SignifyTaskCompletion("task-id"))
+	a.Equal("/v1/task/completion", path)
+}
@@ -4,13 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"reflect"
+	"slices"
 	"strings"
-	"time"
 
-	"github.com/conneroisu/groq-go/internal/schema"
 	"github.com/conneroisu/groq-go/pkg/builders"
 	"github.com/conneroisu/groq-go/pkg/groqerr"
 )
@@ -22,16 +21,54 @@ const (
 	chatCompletionsSuffix endpoint = "/chat/completions"
 	transcriptionsSuffix  endpoint = "/audio/transcriptions"
 	translationsSuffix    endpoint = "/audio/translations"
+	speechSuffix          endpoint = "/audio/speech"
 	embeddingsSuffix      endpoint = "/embeddings"
 	moderationsSuffix     endpoint = "/moderations"
 )
 
 // ChatCompletion method is an API call to create a chat completion.
+//
+// If a fallback chain is configured for the request's model, the request
+// is retried against the fallback models according to the client's
+// FallbackPolicy.
 func (c *Client) ChatCompletion(
 	ctx context.Context,
 	request ChatCompletionRequest,
+) (response ChatCompletionResponse, err error) {
+	err = c.withFallback(ctx, request.Model, func(model ChatModel) error {
+		request.Model = model
+		response, err = invoke(
+			ctx,
+			c,
+			"ChatCompletion",
+			string(model),
+			request,
+			c.chatCompletion,
+		)
+		return err
+	})
+	return
+}
+
+// chatCompletion sends a chat completion request to its model.
+func (c *Client) chatCompletion(
+	ctx context.Context,
+	request ChatCompletionRequest,
 ) (response ChatCompletionResponse, err error) {
 	request.Stream = false
+	err = checkContextWindow(request)
+	if err != nil {
+		return
+	}
+	err = checkImages(request)
+	if err != nil {
+		return
+	}
+	estimated := EstimateTokens(request.Messages)
+	err = c.acquire(ctx, string(request.Model), estimated)
+	if err != nil {
+		return
+	}
 	req, err := builders.NewRequest(
 		ctx,
 		c.header,
@@ -41,23 +78,67 @@ func (c *Client) ChatCompletion(
 	if err != nil {
 		return
 	}
-	err = c.sendRequest(req, &response)
-	reqErr, ok := err.(*groqerr.APIError)
-	if ok && (reqErr.HTTPStatusCode == http.StatusServiceUnavailable ||
-		reqErr.HTTPStatusCode == http.StatusInternalServerError) {
-		time.Sleep(request.RetryDelay)
-		return c.ChatCompletion(ctx, request)
+	policy := c.retryPolicy
+	if request.RetryDelay > 0 {
+		policy.InitialBackoff = request.RetryDelay
+	}
+	err = c.sendRequestRetry(req, &response, policy)
+	if err != nil {
+		c.observe(string(request.Model), estimated, nil, response.RateLimits())
+		return
 	}
+	response.AnsweredBy = request.Model
+	c.observe(
+		string(request.Model),
+		estimated,
+		&response.Usage,
+		response.RateLimits(),
+	)
 	return
 }
 
 // ChatCompletionStream method is an API call to create a chat completion
 // w/ streaming support.
+//
+// Fallback models are only tried while opening the stream.
 func (c *Client) ChatCompletionStream(
 	ctx context.Context,
 	request ChatCompletionRequest,
+) (stream *ChatCompletionStream, err error) {
+	err = c.withFallback(ctx, request.Model, func(model ChatModel) error {
+		request.Model = model
+		stream, err = invoke(
+			ctx,
+			c,
+			"ChatCompletionStream",
+			string(model),
+			request,
+			c.chatCompletionStream,
+		)
+		return err
+	})
+	return
+}
+
+// chatCompletionStream opens a chat completion stream with its model.
+func (c *Client) chatCompletionStream(
+	ctx context.Context,
+	request ChatCompletionRequest,
 ) (stream *ChatCompletionStream, err error) {
 	request.Stream = true
+	err = checkContextWindow(request)
+	if err != nil {
+		return
+	}
+	err = checkImages(request)
+	if err != nil {
+		return
+	}
+	estimated := EstimateTokens(request.Messages)
+	err = c.acquire(ctx, string(request.Model), estimated)
+	if err != nil {
+		return nil, err
+	}
 	req, err := builders.NewRequest(
 		ctx,
 		c.header,
@@ -74,54 +155,81 @@ func (c *Client) ChatCompletionStream(
 	if err != nil {
 		return
 	}
-	return &ChatCompletionStream{
+	stream = &ChatCompletionStream{
 		StreamReader: resp,
-	}, nil
+		AnsweredBy:   request.Model,
+	}
+	c.observe(string(request.Model), estimated, nil, stream.RateLimits())
+	return stream, nil
 }
 
 // ChatCompletionJSON method is an API call to create a chat completion
 // w/ object output.
+//
+// The reply is validated against the schema reflected from the output. If
+// it does not match, the model is re-prompted with the violations up to
+// request.RepairAttempts times before a groqerr.ErrSchemaValidation is
+// returned.
 func (c *Client) ChatCompletionJSON(
 	ctx context.Context,
 	request ChatCompletionRequest,
 	output any,
 ) (err error) {
-	schema, err := schema.ReflectSchema(reflect.TypeOf(output))
+	request.ResponseFormat, err = jsonResponseFormat(output)
 	if err != nil {
 		return err
 	}
-	request.ResponseFormat = &ChatResponseFormat{
-		JSONSchema: &JSONSchema{
-			Name:        schema.Title,
-			Description: schema.Description,
-			Schema:      *schema,
-			Strict:      true,
-		},
-		Type: FormatJSON,
-	}
-	response, err := c.ChatCompletion(ctx, request)
-	if err != nil {
-		reqErr, ok := err.(*groqerr.APIError)
-		if ok && (reqErr.HTTPStatusCode == http.StatusServiceUnavailable ||
-			reqErr.HTTPStatusCode == http.StatusInternalServerError) {
-			time.Sleep(request.RetryDelay)
-			return c.ChatCompletionJSON(ctx, request, output)
+	for attempt := 0; ; attempt++ {
+		response, err := c.ChatCompletion(ctx, request)
+		if err != nil {
+			return err
 		}
+		if len(response.Choices) == 0 {
+			return fmt.Errorf("response (%s) has no choices", response.ID)
+		}
+		content := extractJSON(response.Choices[0].Message.Content)
+		err = request.ResponseFormat.JSONSchema.Schema.Validate([]byte(content))
+		if err != nil {
+			if attempt >= request.RepairAttempts {
+				return err
+			}
+			request.Messages = append(
+				slices.Clip(request.Messages),
+				response.Choices[0].Message,
+				ChatCompletionMessage{
+					Role:    RoleUser,
+					Content: repairPrompt(err),
+				},
+			)
+			continue
+		}
+		err = json.Unmarshal([]byte(content), &output)
+		if err != nil {
+			return fmt.Errorf(
+				"error unmarshalling response (%s) to output: %v",
+				response.ID,
+				err,
+			)
+		}
+		return nil
 	}
-	content := response.Choices[0].Message.Content
-	split := strings.Split(content, "```")
-	if len(split) > 1 {
-		content = split[1]
-	}
-	err = json.Unmarshal([]byte(content), &output)
-	if err != nil {
-		return fmt.Errorf(
-			"error unmarshalling response (%s) to output: %v",
-			response.ID,
-			err,
-		)
+}
+
+// repairPrompt returns the message asking the model to fix a reply that
+// failed the schema validation.
+func repairPrompt(err error) string {
+	var b strings.Builder
+	b.WriteString("Your previous reply does not match the required JSON schema:\n")
+	var validationErr groqerr.ErrSchemaValidation
+	if errors.As(err, &validationErr) {
+		for _, v := range validationErr.Violations {
+			b.WriteString("- " + v.String() + "\n")
+		}
+	} else {
+		b.WriteString("- " + err.Error() + "\n")
 	}
-	return
+	b.WriteString("Reply again with only the corrected JSON.")
+	return b.String()
 }
 
 // Moderate performs a moderation api call over a string.
@@ -131,6 +239,44 @@ func (c *Client) Moderate(
 	messages []ChatCompletionMessage,
 	model ModerationModel,
 ) (response []Moderation, err error) {
+	resp, err := c.ModerateWithResponse(ctx, messages, model)
+	return resp.Categories, err
+}
+
+// ModerateWithResponse performs a moderation api call like Moderate and
+// returns the categories of harmful content found along with the rate
+// limits of the response.
+func (c *Client) ModerateWithResponse(
+	ctx context.Context,
+	messages []ChatCompletionMessage,
+	model ModerationModel,
+) (response ModerationResponse, err error) {
+	return invoke(
+		ctx,
+		c,
+		"Moderate",
+		string(model),
+		messages,
+		func(
+			ctx context.Context,
+			messages []ChatCompletionMessage,
+		) (ModerationResponse, error) {
+			return c.moderate(ctx, messages, model)
+		},
+	)
+}
+
+// moderate sends the messages to the moderation model.
+func (c *Client) moderate(
+	ctx context.Context,
+	messages []ChatCompletionMessage,
+	model ModerationModel,
+) (response ModerationResponse, err error) {
+	estimated := EstimateTokens(messages)
+	err = c.acquire(ctx, string(model), estimated)
+	if err != nil {
+		return
+	}
 	req, err := builders.NewRequest(
 		ctx,
 		c.header,
@@ -149,17 +295,20 @@ func (c *Client) Moderate(
 	}
 	var resp ChatCompletionResponse
 	err = c.sendRequest(req, &resp)
+	response.header = resp.header
 	if err != nil {
+		c.observe(string(model), estimated, nil, resp.RateLimits())
 		return
 	}
+	c.observe(string(model), estimated, &resp.Usage, resp.RateLimits())
 	if strings.Contains(resp.Choices[0].Message.Content, "unsafe") {
 		split := strings.Split(
 			strings.Split(resp.Choices[0].Message.Content, "\n")[1],
 			",",
 		)
 		for _, s := range split {
-			response = append(
-				response,
+			response.Categories = append(
+				response.Categories,
 				sectionMap[strings.TrimSpace(s)],
 			)
 		}
@@ -167,6 +316,60 @@ func (c *Client) Moderate(
 	return
 }
 
+// Embeddings method is an API call to create embeddings for the given
+// input.
+func (c *Client) Embeddings(
+	ctx context.Context,
+	request EmbeddingRequest,
+) (response EmbeddingResponse, err error) {
+	return invoke(
+		ctx,
+		c,
+		"Embeddings",
+		string(request.Model),
+		request,
+		c.embeddings,
+	)
+}
+
+// embeddings sends an embeddings request.
+func (c *Client) embeddings(
+	ctx context.Context,
+	request EmbeddingRequest,
+) (response EmbeddingResponse, err error) {
+	err = request.validate()
+	if err != nil {
+		return
+	}
+	estimated := request.estimateTokens()
+	err = c.acquire(ctx, string(request.Model), estimated)
+	if err != nil {
+		return
+	}
+	req, err := builders.NewRequest(
+		ctx,
+		c.header,
+		http.MethodPost,
+		c.fullURL(embeddingsSuffix, withModel(request.Model)),
+		builders.WithBody(request),
+	)
+	if err != nil {
+		return
+	}
+	err = c.sendRequest(req, &response)
+	if err != nil {
+		c.observe(string(request.Model), estimated, nil, response.RateLimits())
+		return
+	}
+	c.observe(
+		string(request.Model),
+		estimated,
+		&response.Usage,
+		response.RateLimits(),
+	)
+	return
+}
+
 // Transcribe calls the transcriptions endpoint with the given request.
 //
 // Returns transcribed text in the response_format specified in the request.
@@ -174,7 +377,16 @@ func (c *Client) Transcribe(
 	ctx context.Context,
 	request AudioRequest,
 ) (AudioResponse, error) {
-	return c.callAudioAPI(ctx, request, transcriptionsSuffix)
+	return invoke(
+		ctx,
+		c,
+		"Transcribe",
+		string(request.Model),
+		request,
+		func(ctx context.Context, request AudioRequest) (AudioResponse, error) {
+			return c.callAudioAPI(ctx, request, transcriptionsSuffix)
+		},
+	)
 }
 
 // Translate calls the translations endpoint with the given request.
@@ -184,7 +396,16 @@ func (c *Client) Translate(
 	ctx context.Context,
 	request AudioRequest,
 ) (AudioResponse, error) {
-	return c.callAudioAPI(ctx, request, translationsSuffix)
+	return invoke(
+		ctx,
+		c,
+		"Translate",
+		string(request.Model),
+		request,
+		func(ctx context.Context, request AudioRequest) (AudioResponse, error) {
+			return c.callAudioAPI(ctx, request, translationsSuffix)
+		},
+	)
 }
 
 // callAudioAPI calls the audio API with the given request.
@@ -195,9 +416,13 @@ func (c *Client) callAudioAPI(
 	request AudioRequest,
 	endpointSuffix endpoint,
 ) (response AudioResponse, err error) {
+	err = c.acquire(ctx, string(request.Model), 0)
+	if err != nil {
+		return AudioResponse{}, err
+	}
 	var formBody bytes.Buffer
-	c.requestFormBuilder = builders.NewFormBuilder(&formBody)
-	err = audioMultipartForm(request, c.requestFormBuilder)
+	builder := builders.NewFormBuilder(&formBody)
+	err = audioMultipartForm(request, builder)
 	if err != nil {
 		return AudioResponse{}, err
 	}
@@ -207,7 +432,7 @@ func (c *Client) callAudioAPI(
 		http.MethodPost,
 		c.fullURL(endpointSuffix, withModel(request.Model)),
 		builders.WithBody(&formBody),
-		builders.WithContentType(c.requestFormBuilder.FormDataContentType()),
+		builders.WithContentType(builder.FormDataContentType()),
 	)
 	if err != nil {
 		return AudioResponse{}, err
@@ -220,6 +445,7 @@ func (c *Client) callAudioAPI(
 		err = c.sendRequest(req, &textResponse)
 		response = textResponse.toAudioResponse()
 	}
+	c.observe(string(request.Model), 0, nil, response.RateLimits())
 	if err != nil {
 		return AudioResponse{}, err
 	}
@@ -0,0 +1,3 @@
+// Package audio contains the splitting of wav, mp3 and flac audio into
+// overlapping chunks for long audio transcriptions.
+package audio
@@ -0,0 +1,211 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	// flacStreamInfoSize is the size of the streaminfo metadata block.
+	flacStreamInfoSize = 34
+	// flacTotalSamplesMask masks the total samples of the 64 bits of the
+	// streaminfo holding the sample rate, channels and bits per sample.
+	flacTotalSamplesMask = 1<<36 - 1
+)
+
+// flacFrame is the header of a flac frame.
+type flacFrame struct {
+	// number is the frame number of fixed block size streams and the
+	// sample number of variable block size streams.
+	number   uint64
+	variable bool
+	samples  int
+}
+
+// parseFLAC parses flac audio into units of frames.
+//
+// Frames are found by their sync code and header crc and must follow each
+// other. Chunks keep only the streaminfo metadata block, with the total
+// samples and md5 signature cleared as they describe the whole audio.
+// Frames are not decoded, so the level of a frame is its size per sample,
+// which is smallest for silence.
+func parseFLAC(data []byte) (container, error) {
+	pos := 4
+	var streamInfo []byte
+	for last := false; !last; {
+		if pos+4 > len(data) {
+			return container{}, errors.New("flac metadata is truncated")
+		}
+		last = data[pos]&0x80 != 0
+		typ := data[pos] & 0x7F
+		size := int(data[pos+1])<<16 | int(data[pos+2])<<8 | int(data[pos+3])
+		pos += 4
+		if pos+size > len(data) {
+			return container{}, errors.New("flac metadata is truncated")
+		}
+		if typ == 0 && size == flacStreamInfoSize {
+			streamInfo = data[pos : pos+size]
+		}
+		pos += size
+	}
+	if streamInfo == nil {
+		return container{}, errors.New("flac file is missing its streaminfo")
+	}
+	rate := int(binary.BigEndian.Uint64(streamInfo[10:18]) >> 44)
+	if rate == 0 {
+		return container{}, errors.New("flac file has an invalid sample rate")
+	}
+	var (
+		units []unit
+		start float64
+	)
+	for pos < len(data) {
+		frame, ok := parseFLACFrame(data[pos:])
+		if !ok {
+			return container{}, errors.New("flac frame header is invalid")
+		}
+		end := len(data)
+		for next := pos + 2; next+2 <= len(data); next++ {
+			if data[next] != 0xFF || data[next+1]&0xFE != 0xF8 {
+				continue
+			}
+			following, ok := parseFLACFrame(data[next:])
+			if ok && following.variable == frame.variable &&
+				following.number == frame.next() {
+				end = next
+				break
+			}
+		}
+		dur := float64(frame.samples) / float64(rate)
+		units = append(units, unit{
+			offset: pos,
+			size:   end - pos,
+			start:  start,
+			dur:    dur,
+			level:  float64(end-pos) / float64(frame.samples),
+		})
+		start += dur
+		pos = end
+	}
+	header := make([]byte, 0, 8+flacStreamInfoSize)
+	header = append(header, "fLaC"...)
+	header = append(header, 0x80, 0, 0, flacStreamInfoSize)
+	header = append(header, streamInfo...)
+	info := header[8:]
+	binary.BigEndian.PutUint64(
+		info[10:18],
+		binary.BigEndian.Uint64(info[10:18])&^flacTotalSamplesMask,
+	)
+	clear(info[18:])
+	return container{
+		units:    units,
+		overhead: len(header),
+		encode: func(body []byte) []byte {
+			return append(append([]byte(nil), header...), body...)
+		},
+	}, nil
+}
+
+// next returns the number of the frame following the frame.
+func (f flacFrame) next() uint64 {
+	if f.variable {
+		return f.number + uint64(f.samples)
+	}
+	return f.number + 1
+}
+
+// parseFLACFrame parses and checks the header of the flac frame starting
+// the data.
+func parseFLACFrame(data []byte) (flacFrame, bool) {
+	if len(data) < 6 || data[0] != 0xFF || data[1]&0xFE != 0xF8 {
+		return flacFrame{}, false
+	}
+	frame := flacFrame{variable: data[1]&1 == 1}
+	blockCode := data[2] >> 4
+	rateCode := data[2] & 0x0F
+	channels := data[3] >> 4
+	if blockCode == 0 || rateCode == 15 || channels > 10 ||
+		data[3]>>1&7 == 3 || data[3]&1 != 0 {
+		return flacFrame{}, false
+	}
+	pos := 4
+	// the frame or sample number is coded like an extended utf-8 rune
+	first := data[pos]
+	extra := 0
+	switch {
+	case first&0x80 == 0:
+		frame.number = uint64(first)
+	case first&0xE0 == 0xC0:
+		frame.number, extra = uint64(first&0x1F), 1
+	case first&0xF0 == 0xE0:
+		frame.number, extra = uint64(first&0x0F), 2
+	case first&0xF8 == 0xF0:
+		frame.number, extra = uint64(first&0x07), 3
+	case first&0xFC == 0xF8:
+		frame.number, extra = uint64(first&0x03), 4
+	case first&0xFE == 0xFC:
+		frame.number, extra = uint64(first&0x01), 5
+	case first == 0xFE:
+		frame.number, extra = 0, 6
+	default:
+		return flacFrame{}, false
+	}
+	pos++
+	if pos+extra > len(data) {
+		return flacFrame{}, false
+	}
+	for _, b := range data[pos : pos+extra] {
+		if b&0xC0 != 0x80 {
+			return flacFrame{}, false
+		}
+		frame.number = frame.number<<6 | uint64(b&0x3F)
+	}
+	pos += extra
+	switch {
+	case blockCode == 1:
+		frame.samples = 192
+	case blockCode <= 5:
+		frame.samples = 576 << (blockCode - 2)
+	case blockCode == 6:
+		if pos+1 > len(data) {
+			return flacFrame{}, false
+		}
+		frame.samples = int(data[pos]) + 1
+		pos++
+	case blockCode == 7:
+		if pos+2 > len(data) {
+			return flacFrame{}, false
+		}
+		frame.samples = int(binary.BigEndian.Uint16(data[pos:])) + 1
+		pos += 2
+	default:
+		frame.samples = 256 << (blockCode - 8)
+	}
+	switch rateCode {
+	case 12:
+		pos++
+	case 13, 14:
+		pos += 2
+	}
+	if pos >= len(data) || crc8(data[:pos]) != data[pos] {
+		return flacFrame{}, false
+	}
+	return frame, true
+}
+
+// crc8 returns the crc-8 of a flac frame header, with the polynomial
+// x^8 + x^2 + x + 1.
+func crc8(data []byte) byte {
+	var crc byte
+	for _, b := range data {
+		crc ^= b
+		for range 8 {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,135 @@
+package audio
+
+import (
+	"bytes"
+	"errors"
+)
+
+var (
+	// mp3Bitrates are the bitrates in kbps of the bitrate indices of the
+	// mpeg version 1 layers I, II and III and version 2 layers I and II/III.
+	mp3Bitrates = [5][16]int{
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448},
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384},
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+	}
+	// mp3SampleRates are the sample rates of the sample rate indices of
+	// mpeg version 1.
+	mp3SampleRates = [3]int{44100, 48000, 32000}
+)
+
+// mp3Header is the header of an mp3 frame.
+type mp3Header struct {
+	size    int
+	samples int
+	rate    int
+}
+
+// parseMP3 parses mp3 audio into units of frames.
+//
+// Id3 tags and the xing or info frame carrying the length of the whole
+// audio are left out of the chunks. Frames are not decoded, so the level of
+// a frame is its bitrate, which variable bitrate encoders lower for
+// silence.
+func parseMP3(data []byte) (container, error) {
+	pos := 0
+	if bytes.HasPrefix(data, []byte("ID3")) && len(data) >= 10 {
+		size := int(data[6])<<21 | int(data[7])<<14 | int(data[8])<<7 | int(data[9])
+		pos = 10 + size
+		if data[5]&0x10 != 0 {
+			pos += 10
+		}
+	}
+	var (
+		units []unit
+		start float64
+	)
+	for pos+4 <= len(data) {
+		header, ok := parseMP3Header(data[pos:])
+		if !ok || pos+header.size > len(data) {
+			// resynchronize on the next frame
+			pos++
+			continue
+		}
+		frame := data[pos : pos+header.size]
+		if len(units) == 0 && isXingFrame(frame) {
+			pos += header.size
+			continue
+		}
+		dur := float64(header.samples) / float64(header.rate)
+		units = append(units, unit{
+			offset: pos,
+			size:   header.size,
+			start:  start,
+			dur:    dur,
+			level:  float64(header.size) / dur,
+		})
+		start += dur
+		pos += header.size
+	}
+	if len(units) == 0 {
+		return container{}, errors.New("mp3 audio has no frames")
+	}
+	return container{
+		units:  units,
+		encode: func(body []byte) []byte { return body },
+	}, nil
+}
+
+// parseMP3Header parses the header of the mp3 frame starting the data.
+func parseMP3Header(data []byte) (mp3Header, bool) {
+	if len(data) < 4 || data[0] != 0xFF || data[1]&0xE0 != 0xE0 {
+		return mp3Header{}, false
+	}
+	version := data[1] >> 3 & 3 // 0: 2.5, 2: 2, 3: 1
+	layer := data[1] >> 1 & 3   // 1: III, 2: II, 3: I
+	bitrateIndex := data[2] >> 4
+	rateIndex := data[2] >> 2 & 3
+	padding := int(data[2] >> 1 & 1)
+	if version == 1 || layer == 0 || bitrateIndex == 0 ||
+		bitrateIndex == 15 || rateIndex == 3 {
+		return mp3Header{}, false
+	}
+	rate := mp3SampleRates[rateIndex]
+	switch version {
+	case 2:
+		rate /= 2
+	case 0:
+		rate /= 4
+	}
+	var table int
+	switch {
+	case version == 3:
+		table = int(3 - layer)
+	case layer == 3:
+		table = 3
+	default:
+		table = 4
+	}
+	bitrate := mp3Bitrates[table][bitrateIndex] * 1000
+	var header mp3Header
+	switch {
+	case layer == 3:
+		header.samples = 384
+		header.size = (12*bitrate/rate + padding) * 4
+	case layer == 2 || version == 3:
+		header.samples = 1152
+		header.size = 144*bitrate/rate + padding
+	default:
+		header.samples = 576
+		header.size = 72*bitrate/rate + padding
+	}
+	header.rate = rate
+	return header, header.size > 4
+}
+
+// isXingFrame reports whether the frame is a xing, info or vbri frame
+// describing the whole audio rather than carrying samples.
+func isXingFrame(frame []byte) bool {
+	head := frame[:min(len(frame), 64)]
+	return bytes.Contains(head, []byte("Xing")) ||
+		bytes.Contains(head, []byte("Info")) ||
+		bytes.Contains(head, []byte("VBRI"))
+}
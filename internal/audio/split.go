@@ -0,0 +1,165 @@
+package audio
+
+import (
+	"bytes"
+	"errors"
+)
+
+// ErrUnsupportedFormat is returned when the audio is not wav, mp3 or flac.
+var ErrUnsupportedFormat = errors.New("unsupported audio format, expected wav, mp3 or flac")
+
+type (
+	// Chunk is a chunk of audio encoded in the format of the split audio.
+	Chunk struct {
+		// Data is the encoded audio of the chunk.
+		Data []byte
+		// Start is the offset of the chunk in the audio in seconds.
+		Start float64
+		// End is the end of the chunk in the audio in seconds.
+		End float64
+	}
+	// Options configures how audio is split.
+	Options struct {
+		// MaxDuration is the longest duration of a chunk in seconds.
+		MaxDuration float64
+		// MaxBytes is the largest size of an encoded chunk.
+		MaxBytes int
+		// Overlap is the duration in seconds shared by consecutive
+		// chunks.
+		Overlap float64
+		// SilenceWindow is the duration in seconds before the end of a
+		// full chunk searched for the quietest point to cut at.
+		SilenceWindow float64
+	}
+	// unit is the smallest piece of audio a chunk can be cut at, such as a
+	// frame.
+	unit struct {
+		offset, size int
+		start, dur   float64
+		// level is the loudness of the unit, the quietest unit of the
+		// silence window being cut at.
+		level float64
+	}
+	// container is audio parsed into contiguous units.
+	container struct {
+		units []unit
+		// encode returns a chunk of the units in the format of the
+		// audio.
+		encode func(body []byte) []byte
+		// overhead is the size added to the units by encode.
+		overhead int
+	}
+)
+
+// Ext returns the file extension of the format of the audio, such as
+// ".wav", or an empty string if it is not supported.
+func Ext(data []byte) string {
+	switch {
+	case isWAV(data):
+		return ".wav"
+	case isFLAC(data):
+		return ".flac"
+	case isMP3(data):
+		return ".mp3"
+	default:
+		return ""
+	}
+}
+
+// Split splits wav, mp3 or flac audio into chunks shorter than MaxDuration
+// and smaller than MaxBytes, overlapping by Overlap.
+//
+// Chunks are cut at the quietest point of the SilenceWindow before their
+// end. It also returns the duration of the audio in seconds. Audio fitting
+// in a single chunk is returned unchanged.
+func Split(data []byte, opts Options) ([]Chunk, float64, error) {
+	var (
+		c   container
+		err error
+	)
+	switch Ext(data) {
+	case ".wav":
+		c, err = parseWAV(data)
+	case ".flac":
+		c, err = parseFLAC(data)
+	case ".mp3":
+		c, err = parseMP3(data)
+	default:
+		return nil, 0, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(c.units) == 0 {
+		return nil, 0, errors.New("audio has no frames")
+	}
+	units := c.units
+	duration := units[len(units)-1].start + units[len(units)-1].dur
+	if duration <= opts.MaxDuration && len(data) <= opts.MaxBytes {
+		return []Chunk{{Data: data, End: duration}}, duration, nil
+	}
+	var chunks []Chunk
+	for first := 0; ; {
+		last := first
+		for last < len(units) &&
+			units[last].start+units[last].dur-units[first].start <= opts.MaxDuration &&
+			units[last].offset+units[last].size-units[first].offset+c.overhead <= opts.MaxBytes {
+			last++
+		}
+		if last == first {
+			// a single unit exceeds the limits
+			last++
+		}
+		if last < len(units) {
+			last = quietest(units, first, last, opts.SilenceWindow) + 1
+		}
+		chunks = append(chunks, Chunk{
+			Data:  c.encode(data[units[first].offset : units[last-1].offset+units[last-1].size]),
+			Start: units[first].start,
+			End:   units[last-1].start + units[last-1].dur,
+		})
+		if last == len(units) {
+			return chunks, duration, nil
+		}
+		next := last
+		for next > first+1 && units[last].start-units[next-1].start <= opts.Overlap {
+			next--
+		}
+		first = next
+	}
+}
+
+// quietest returns the index of the quietest unit within the window before
+// the end of the units from first to last, preferring later units.
+func quietest(units []unit, first, last int, window float64) int {
+	end := units[last-1].start + units[last-1].dur
+	best := last - 1
+	for i := last - 1; i > first && end-units[i].start <= window; i-- {
+		if units[i].level < units[best].level {
+			best = i
+		}
+	}
+	return best
+}
+
+// isWAV reports whether the data is a wav file.
+func isWAV(data []byte) bool {
+	return len(data) >= 12 &&
+		bytes.Equal(data[:4], []byte("RIFF")) &&
+		bytes.Equal(data[8:12], []byte("WAVE"))
+}
+
+// isFLAC reports whether the data is a flac file.
+func isFLAC(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("fLaC"))
+}
+
+// isMP3 reports whether the data is an mp3 file, starting with an id3 tag
+// or a frame.
+func isMP3(data []byte) bool {
+	if bytes.HasPrefix(data, []byte("ID3")) {
+		return true
+	}
+	_, ok := parseMP3Header(data)
+	return ok
+}
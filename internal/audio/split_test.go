@@ -0,0 +1,192 @@
+package audio
+
+import (
+	"encoding/binary"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wavFile returns a mono 16 bit wav file of the samples.
+func wavFile(rate int, samples []int16) []byte {
+	fmtChunk := make([]byte, 16)
+	binary.LittleEndian.PutUint16(fmtChunk[0:], 1)
+	binary.LittleEndian.PutUint16(fmtChunk[2:], 1)
+	binary.LittleEndian.PutUint32(fmtChunk[4:], uint32(rate))
+	binary.LittleEndian.PutUint32(fmtChunk[8:], uint32(rate*2))
+	binary.LittleEndian.PutUint16(fmtChunk[12:], 2)
+	binary.LittleEndian.PutUint16(fmtChunk[14:], 16)
+	body := make([]byte, 0, 2*len(samples))
+	for _, s := range samples {
+		body = binary.LittleEndian.AppendUint16(body, uint16(s))
+	}
+	return encodeWAV(fmtChunk, body)
+}
+
+// loudness returns samples of rate per second for the seconds, silent for
+// the seconds in silent.
+func loudness(rate, seconds int, silent ...int) []int16 {
+	samples := make([]int16, rate*seconds)
+	for i := range samples {
+		samples[i] = int16(1000 + i%500)
+		if slices.Contains(silent, i/rate) {
+			samples[i] = 0
+		}
+	}
+	return samples
+}
+
+// checkChunks checks that the chunks cover the duration, overlap and fit
+// the limits.
+func checkChunks(t *testing.T, chunks []Chunk, duration float64, opts Options) {
+	t.Helper()
+	a := assert.New(t)
+	require.NotEmpty(t, chunks)
+	a.Zero(chunks[0].Start)
+	a.InDelta(duration, chunks[len(chunks)-1].End, 1e-9)
+	for i, chunk := range chunks {
+		a.LessOrEqual(chunk.End-chunk.Start, opts.MaxDuration+1e-9)
+		a.LessOrEqual(len(chunk.Data), opts.MaxBytes)
+		if i > 0 {
+			overlap := chunks[i-1].End - chunk.Start
+			a.Greater(overlap, 0.0, "chunk %d should overlap the previous chunk", i)
+			a.Greater(chunk.Start, chunks[i-1].Start)
+		}
+	}
+}
+
+// TestSplitWAV tests that wav audio is cut in its silences.
+func TestSplitWAV(t *testing.T) {
+	a := assert.New(t)
+	const rate = 1000
+	data := wavFile(rate, loudness(rate, 20, 6, 13))
+	a.Equal(".wav", Ext(data))
+	opts := Options{MaxDuration: 8, MaxBytes: 1 << 20, Overlap: 1, SilenceWindow: 3}
+	chunks, duration, err := Split(data, opts)
+	require.NoError(t, err)
+	a.InDelta(20.0, duration, 1e-9)
+	checkChunks(t, chunks, duration, opts)
+	require.Len(t, chunks, 3)
+	a.InDelta(6.0, chunks[0].End, 1.0, "first chunk should end in the silence")
+	for _, chunk := range chunks {
+		parsed, err := parseWAV(chunk.Data)
+		require.NoError(t, err)
+		last := parsed.units[len(parsed.units)-1]
+		a.InDelta(chunk.End-chunk.Start, last.start+last.dur, 1e-9)
+	}
+
+	chunks, _, err = Split(data, Options{MaxDuration: 60, MaxBytes: 1 << 20})
+	require.NoError(t, err)
+	require.Len(t, chunks, 1)
+	a.Equal(data, chunks[0].Data)
+
+	opts = Options{MaxDuration: 60, MaxBytes: 8000, Overlap: 0.5}
+	chunks, duration, err = Split(data, opts)
+	require.NoError(t, err)
+	checkChunks(t, chunks, duration, opts)
+	a.Greater(len(chunks), 5)
+}
+
+// mp3Frame returns an mpeg 1 layer III frame at 44.1kHz of the bitrate
+// index.
+func mp3Frame(bitrateIndex byte, payload string) []byte {
+	header := []byte{0xFF, 0xFB, bitrateIndex << 4, 0x00}
+	h, _ := parseMP3Header(header)
+	frame := make([]byte, h.size)
+	copy(frame, header)
+	copy(frame[36:], payload)
+	return frame
+}
+
+// TestSplitMP3 tests that mp3 audio is cut at its smallest frames,
+// leaving out its id3 tag and xing frame.
+func TestSplitMP3(t *testing.T) {
+	a := assert.New(t)
+	data := []byte{'I', 'D', '3', 3, 0, 0, 0, 0, 0, 4, 0, 0, 0, 0}
+	data = append(data, mp3Frame(9, "Xing")...)
+	frames := 400
+	for i := range frames {
+		index := byte(9)
+		if i%100 == 70 {
+			index = 1
+		}
+		data = append(data, mp3Frame(index, "")...)
+	}
+	a.Equal(".mp3", Ext(data))
+	frameDur := 1152.0 / 44100
+	opts := Options{MaxDuration: 80 * frameDur, MaxBytes: 1 << 20, Overlap: 5 * frameDur, SilenceWindow: 20 * frameDur}
+	chunks, duration, err := Split(data, opts)
+	require.NoError(t, err)
+	a.InDelta(float64(frames)*frameDur, duration, 1e-9)
+	checkChunks(t, chunks, duration, opts)
+	a.InDelta(71*frameDur, chunks[0].End, 1e-9, "first chunk should end after the smallest frame")
+	for _, chunk := range chunks {
+		h, ok := parseMP3Header(chunk.Data)
+		require.True(t, ok)
+		a.False(isXingFrame(chunk.Data[:h.size]))
+	}
+}
+
+// flacFrameNumber codes a frame number like an utf-8 rune.
+func flacFrameNumber(n int) []byte {
+	if n < 0x80 {
+		return []byte{byte(n)}
+	}
+	return []byte{0xC0 | byte(n>>6), 0x80 | byte(n&0x3F)}
+}
+
+// flacFile returns a flac file at 16kHz with frames of 256 samples of the
+// payload sizes.
+func flacFile(sizes []int) []byte {
+	info := make([]byte, flacStreamInfoSize)
+	binary.BigEndian.PutUint64(info[10:18], 16000<<44|uint64(len(sizes)*256))
+	copy(info[18:], "md5 signature...")
+	data := append([]byte("fLaC"), 0x80, 0, 0, flacStreamInfoSize)
+	data = append(data, info...)
+	for i, size := range sizes {
+		header := append([]byte{0xFF, 0xF8, 0x80, 0x00}, flacFrameNumber(i)...)
+		header = append(header, crc8(header))
+		data = append(data, header...)
+		data = append(data, make([]byte, size)...)
+	}
+	return data
+}
+
+// TestSplitFLAC tests that flac audio is cut at its smallest frames and
+// chunks keep a streaminfo without the totals of the whole audio.
+func TestSplitFLAC(t *testing.T) {
+	a := assert.New(t)
+	sizes := make([]int, 300)
+	for i := range sizes {
+		sizes[i] = 200
+		if i%100 == 50 {
+			sizes[i] = 10
+		}
+	}
+	data := flacFile(sizes)
+	a.Equal(".flac", Ext(data))
+	frameDur := 256.0 / 16000
+	opts := Options{MaxDuration: 60 * frameDur, MaxBytes: 1 << 20, Overlap: 5 * frameDur, SilenceWindow: 20 * frameDur}
+	chunks, duration, err := Split(data, opts)
+	require.NoError(t, err)
+	a.InDelta(300*frameDur, duration, 1e-9)
+	checkChunks(t, chunks, duration, opts)
+	a.InDelta(51*frameDur, chunks[0].End, 1e-9)
+	for _, chunk := range chunks {
+		parsed, err := parseFLAC(chunk.Data)
+		require.NoError(t, err)
+		a.InDelta(chunk.End-chunk.Start, float64(len(parsed.units))*frameDur, 1e-9)
+		info := chunk.Data[8 : 8+flacStreamInfoSize]
+		a.Zero(binary.BigEndian.Uint64(info[10:18]) & flacTotalSamplesMask)
+		a.Equal(make([]byte, 16), info[18:])
+	}
+}
+
+// TestSplitUnsupported tests that other formats are rejected.
+func TestSplitUnsupported(t *testing.T) {
+	_, _, err := Split([]byte("OggS not supported"), Options{})
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+	assert.Empty(t, Ext([]byte("OggS")))
+}
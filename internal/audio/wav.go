@@ -0,0 +1,139 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+const (
+	// wavUnitDuration is the duration of the units wav audio is cut at.
+	wavUnitDuration = 0.02
+	// wavFloat is the format tag of ieee float samples.
+	wavFloat = 3
+)
+
+// wavFormat is the fmt chunk of a wav file.
+type wavFormat struct {
+	tag        uint16
+	channels   int
+	sampleRate int
+	blockAlign int
+	bits       int
+}
+
+// parseWAV parses a wav file into units of 20ms of samples.
+func parseWAV(data []byte) (container, error) {
+	var (
+		fmtChunk []byte
+		format   wavFormat
+		body     []byte
+		offset   int
+	)
+	for pos := 12; pos+8 <= len(data); {
+		id := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		start := pos + 8
+		if size > len(data)-start {
+			size = len(data) - start
+		}
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return container{}, errors.New("wav fmt chunk is too short")
+			}
+			fmtChunk = data[start : start+size]
+			format = wavFormat{
+				tag:        binary.LittleEndian.Uint16(fmtChunk[0:2]),
+				channels:   int(binary.LittleEndian.Uint16(fmtChunk[2:4])),
+				sampleRate: int(binary.LittleEndian.Uint32(fmtChunk[4:8])),
+				blockAlign: int(binary.LittleEndian.Uint16(fmtChunk[12:14])),
+				bits:       int(binary.LittleEndian.Uint16(fmtChunk[14:16])),
+			}
+		case "data":
+			body = data[start : start+size]
+			offset = start
+		}
+		pos = start + size + size%2
+	}
+	if fmtChunk == nil || body == nil {
+		return container{}, errors.New("wav file is missing its fmt or data chunk")
+	}
+	if format.sampleRate == 0 || format.blockAlign == 0 ||
+		format.channels == 0 || format.blockAlign%format.channels != 0 {
+		return container{}, errors.New("wav file has an invalid fmt chunk")
+	}
+	frames := max(1, int(float64(format.sampleRate)*wavUnitDuration))
+	unitSize := frames * format.blockAlign
+	var units []unit
+	for pos := 0; pos+format.blockAlign <= len(body); pos += unitSize {
+		size := min(unitSize, len(body)-pos)
+		size -= size % format.blockAlign
+		units = append(units, unit{
+			offset: offset + pos,
+			size:   size,
+			start:  float64(pos/format.blockAlign) / float64(format.sampleRate),
+			dur:    float64(size/format.blockAlign) / float64(format.sampleRate),
+			level:  format.rms(body[pos : pos+size]),
+		})
+	}
+	return container{
+		units:    units,
+		overhead: 28 + len(fmtChunk),
+		encode: func(body []byte) []byte {
+			return encodeWAV(fmtChunk, body)
+		},
+	}, nil
+}
+
+// encodeWAV returns a wav file with the fmt chunk and samples.
+func encodeWAV(fmtChunk, body []byte) []byte {
+	out := make([]byte, 0, 28+len(fmtChunk)+len(body))
+	out = append(out, "RIFF"...)
+	out = binary.LittleEndian.AppendUint32(out, uint32(20+len(fmtChunk)+len(body)))
+	out = append(out, "WAVEfmt "...)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(fmtChunk)))
+	out = append(out, fmtChunk...)
+	out = append(out, "data"...)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(body)))
+	return append(out, body...)
+}
+
+// rms returns the root mean square of the samples, scaled to [0, 1].
+func (f wavFormat) rms(samples []byte) float64 {
+	width := f.blockAlign / f.channels
+	if len(samples) < width {
+		return 0
+	}
+	var sum float64
+	n := 0
+	for pos := 0; pos+width <= len(samples); pos += width {
+		v := f.sample(samples[pos : pos+width])
+		sum += v * v
+		n++
+	}
+	return math.Sqrt(sum / float64(n))
+}
+
+// sample decodes a sample to [-1, 1].
+func (f wavFormat) sample(b []byte) float64 {
+	switch {
+	case f.tag == wavFloat && len(b) == 4:
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+	case f.tag == wavFloat && len(b) == 8:
+		return math.Float64frombits(binary.LittleEndian.Uint64(b))
+	case len(b) == 1:
+		return (float64(b[0]) - 128) / 128
+	}
+	// little endian signed integers of any width, read from the most
+	// significant bytes
+	var v int64
+	for i := len(b) - 1; i >= max(0, len(b)-4); i-- {
+		v = v<<8 | int64(b[i])
+	}
+	bits := 8 * min(len(b), 4)
+	if v >= 1<<(bits-1) {
+		v -= 1 << bits
+	}
+	return float64(v) / float64(int64(1)<<(bits-1))
+}
@@ -0,0 +1,150 @@
+// Package partialjson completes truncated JSON documents so that they can
+// be decoded while they are still being streamed.
+package partialjson
+
+const (
+	// containerObject is an object on the container stack.
+	containerObject containerKind = iota
+	// containerArray is an array on the container stack.
+	containerArray
+)
+
+type (
+	// containerKind is the kind of an open JSON container.
+	containerKind int
+	// container is an open JSON container.
+	container struct {
+		kind containerKind
+		// expectKey reports whether the next string of an object is a
+		// key.
+		expectKey bool
+	}
+)
+
+// Complete returns the longest decodable JSON document described by the
+// given prefix.
+//
+// Open objects and arrays are closed, a string value being written is
+// terminated and trailing incomplete keys, numbers and literals are
+// dropped. It reports false when the prefix does not contain any
+// decodable value yet.
+func Complete(prefix []byte) ([]byte, bool) {
+	var (
+		stack []container
+		// cut is the length of the prefix ending on a complete value and
+		// closers are the bytes closing the containers open at cut.
+		cut     int
+		closers []byte
+		// inString reports whether the scanner is in a string and
+		// isKey whether that string is an object key.
+		inString bool
+		isKey    bool
+		// escape is the offset of the backslash of the escape sequence
+		// being scanned or -1.
+		escape = -1
+		start  = -1
+	)
+	closersOf := func() []byte {
+		out := make([]byte, 0, len(stack))
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i].kind == containerObject {
+				out = append(out, '}')
+			} else {
+				out = append(out, ']')
+			}
+		}
+		return out
+	}
+	mark := func(at int) {
+		cut = at
+		closers = closersOf()
+	}
+	for i := 0; i < len(prefix); i++ {
+		c := prefix[i]
+		if inString {
+			switch {
+			case escape >= 0:
+				if prefix[escape+1] == 'u' {
+					if i-escape == 5 {
+						escape = -1
+					}
+					continue
+				}
+				escape = -1
+			case c == '\\':
+				escape = i
+			case c == '"':
+				inString = false
+				if isKey {
+					stack[len(stack)-1].expectKey = false
+					continue
+				}
+				mark(i + 1)
+			}
+			continue
+		}
+		if start < 0 {
+			switch c {
+			case ' ', '\t', '\n', '\r':
+				continue
+			}
+			if !isValueStart(c) {
+				return nil, false
+			}
+			start = i
+		}
+		switch c {
+		case '"':
+			inString = true
+			isKey = len(stack) > 0 &&
+				stack[len(stack)-1].kind == containerObject &&
+				stack[len(stack)-1].expectKey
+		case '{':
+			stack = append(stack, container{kind: containerObject, expectKey: true})
+			mark(i + 1)
+		case '[':
+			stack = append(stack, container{kind: containerArray})
+			mark(i + 1)
+		case '}', ']':
+			if len(stack) == 0 {
+				return nil, false
+			}
+			stack = stack[:len(stack)-1]
+			mark(i + 1)
+		case ',':
+			if len(stack) > 0 &&
+				stack[len(stack)-1].kind == containerObject {
+				stack[len(stack)-1].expectKey = true
+			}
+			mark(i)
+		}
+	}
+	if start < 0 {
+		return nil, false
+	}
+	if inString && !isKey {
+		end := len(prefix)
+		if escape >= 0 {
+			end = escape
+		}
+		out := make([]byte, 0, end+1+len(stack))
+		out = append(out, prefix[start:end]...)
+		out = append(out, '"')
+		return append(out, closersOf()...), true
+	}
+	if cut <= start {
+		return nil, false
+	}
+	out := make([]byte, 0, cut+len(closers))
+	out = append(out, prefix[start:cut]...)
+	return append(out, closers...), true
+}
+
+// isValueStart reports whether c can start a JSON value.
+func isValueStart(c byte) bool {
+	switch c {
+	case '{', '[', '"', '-', 't', 'f', 'n':
+		return true
+	}
+	return c >= '0' && c <= '9'
+}
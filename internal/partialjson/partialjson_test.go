@@ -0,0 +1,49 @@
+package partialjson_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/conneroisu/groq-go/internal/partialjson"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestComplete tests the completion of truncated JSON documents.
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		prefix   string
+		expected string
+		ok       bool
+	}{
+		{``, ``, false},
+		{`   `, ``, false},
+		{`{`, `{}`, true},
+		{`{"na`, `{}`, true},
+		{`{"name"`, `{}`, true},
+		{`{"name":`, `{}`, true},
+		{`{"name": "Jo`, `{"name": "Jo"}`, true},
+		{`{"name": "Jo\`, `{"name": "Jo"}`, true},
+		{`{"name": "Jo\u00`, `{"name": "Jo"}`, true},
+		{`{"name": "Joé`, `{"name": "Joé"}`, true},
+		{`{"name": "Jo\"e", "age": 4`, `{"name": "Jo\"e"}`, true},
+		{`{"name": "Joe", "age": 42, "tags": ["a", "b`, `{"name": "Joe", "age": 42, "tags": ["a", "b"]}`, true},
+		{`{"nested": {"ok": tru`, `{"nested": {}}`, true},
+		{`{"nested": {"ok": true}, `, `{"nested": {"ok": true}}`, true},
+		{"```json\n{\"a\": [1, 2", ``, false},
+		{` [1, 2, 3`, `[1, 2]`, true},
+		{`[{"a": "b"}, {"c": "d`, `[{"a": "b"}, {"c": "d"}]`, true},
+		{`"partial str`, `"partial str"`, true},
+		{`{"a": 1}`, `{"a": 1}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			a := assert.New(t)
+			out, ok := partialjson.Complete([]byte(tt.prefix))
+			a.Equal(tt.ok, ok)
+			a.Equal(tt.expected, string(out))
+			if ok {
+				a.True(json.Valid(out), "completed JSON is invalid")
+			}
+		})
+	}
+}
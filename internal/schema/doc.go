@@ -1,2 +0,0 @@
-// Package schema provides an interface for working with JSON Schemas.
-package schema
@@ -0,0 +1,31 @@
+package streams
+
+import (
+	"errors"
+	"io"
+	"iter"
+)
+
+// All returns an iterator over the remaining values of the stream.
+//
+// The iteration stops at the end of the stream or after yielding the
+// first error. The stream is closed when the iteration stops, including
+// when the loop is exited early.
+func (stream *StreamReader[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		defer stream.Close()
+		for {
+			response, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				yield(response, err)
+				return
+			}
+			if !yield(response, nil) {
+				return
+			}
+		}
+	}
+}
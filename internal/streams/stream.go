@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 
 	"github.com/conneroisu/groq-go/pkg/groqerr"
 )
@@ -28,6 +29,15 @@ type (
 		readCloser         io.ReadCloser
 		ErrAccumulator     ErrorAccumulator
 		Header             http.Header // Header is the header of the response.
+
+		observers []observer[T]
+		closeOnce sync.Once
+	}
+	// observer is notified of the values received from a stream and of its
+	// closing.
+	observer[T any] struct {
+		onRecv  func(T, error)
+		onClose func()
 	}
 	// ErrorAccumulator is an interface for a unit that accumulates errors.
 	ErrorAccumulator interface {
@@ -44,9 +54,28 @@ type (
 func (stream *StreamReader[T]) Recv() (response T, err error) {
 	if stream.isFinished {
 		err = io.EOF
-		return response, err
+	} else {
+		response, err = stream.processLines()
 	}
-	return stream.processLines()
+	for _, o := range stream.observers {
+		if o.onRecv != nil {
+			o.onRecv(response, err)
+		}
+	}
+	return response, err
+}
+
+// Observe registers functions called with the result of every Recv of the
+// stream, including the io.EOF ending it, and once when the stream is
+// closed.
+//
+// Either function may be nil. Observe is not safe for concurrent use with
+// Recv and should be called before reading the stream.
+func (stream *StreamReader[T]) Observe(onRecv func(T, error), onClose func()) {
+	stream.observers = append(stream.observers, observer[T]{
+		onRecv:  onRecv,
+		onClose: onClose,
+	})
 }
 
 // processLines processes the lines of the current response in the stream.
@@ -114,6 +143,16 @@ func (stream *StreamReader[T]) UnmarshalError() (errResp *groqerr.ErrorResponse)
 
 // Close closes the stream.
 func (stream *StreamReader[T]) Close() error {
+	stream.closeOnce.Do(func() {
+		for _, o := range stream.observers {
+			if o.onClose != nil {
+				o.onClose()
+			}
+		}
+	})
+	if stream.readCloser == nil {
+		return nil
+	}
 	return stream.readCloser.Close()
 }
 
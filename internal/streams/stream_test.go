@@ -148,3 +148,86 @@ func TestErrorByteWriteErrors(t *testing.T) {
 		t.Fatalf("Did not return error when write failed: %v", err)
 	}
 }
+
+// TestStreamReaderAll tests that All iterates over the stream and closes it.
+func TestStreamReaderAll(t *testing.T) {
+	a := assert.New(t)
+	body := &closeRecorder{Reader: bytes.NewReader([]byte(
+		"data: {\"id\":\"1\"}\n\ndata: {\"id\":\"2\"}\n\ndata: [DONE]\n\n",
+	))}
+	stream := streams.NewStreamReader[groq.ChatCompletionStreamResponse](
+		body,
+		nil,
+		3,
+	)
+	var ids []string
+	for response, err := range stream.All() {
+		a.NoError(err)
+		ids = append(ids, response.ID)
+	}
+	a.Equal([]string{"1", "2"}, ids)
+	a.True(body.closed)
+}
+
+// TestStreamReaderAllBreak tests that exiting the loop early closes the
+// stream.
+func TestStreamReaderAllBreak(t *testing.T) {
+	a := assert.New(t)
+	body := &closeRecorder{Reader: bytes.NewReader([]byte(
+		"data: {\"id\":\"1\"}\n\ndata: {\"id\":\"2\"}\n\ndata: [DONE]\n\n",
+	))}
+	stream := streams.NewStreamReader[groq.ChatCompletionStreamResponse](
+		body,
+		nil,
+		3,
+	)
+	for range stream.All() {
+		break
+	}
+	a.True(body.closed)
+}
+
+// closeRecorder is a reader recording whether it has been closed.
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+// Close records the close.
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+// TestStreamReaderObserve tests that observers see every received value
+// and the closing of the stream once.
+func TestStreamReaderObserve(t *testing.T) {
+	a := assert.New(t)
+	body := &closeRecorder{Reader: bytes.NewReader([]byte(
+		"data: {\"id\":\"1\"}\n\ndata: {\"id\":\"2\"}\n\ndata: [DONE]\n\n",
+	))}
+	stream := streams.NewStreamReader[groq.ChatCompletionStreamResponse](
+		body,
+		nil,
+		3,
+	)
+	var (
+		ids    []string
+		eof    bool
+		closes int
+	)
+	stream.Observe(func(response *groq.ChatCompletionStreamResponse, err error) {
+		if errors.Is(err, io.EOF) {
+			eof = true
+			return
+		}
+		a.NoError(err)
+		ids = append(ids, response.ID)
+	}, func() { closes++ })
+	for range stream.All() {
+	}
+	a.NoError(stream.Close())
+	a.Equal([]string{"1", "2"}, ids)
+	a.True(eof)
+	a.Equal(1, closes)
+}
@@ -0,0 +1,174 @@
+package groq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+	"unicode"
+
+	"github.com/conneroisu/groq-go/internal/partialjson"
+	"github.com/conneroisu/groq-go/pkg/jsonschema"
+)
+
+type (
+	// JSONStream is a stream of progressively decoded snapshots of a
+	// structured chat completion.
+	JSONStream[T any] struct {
+		stream  *ChatCompletionStream
+		schema  *jsonschema.Schema
+		acc     ChatCompletionAccumulator
+		content strings.Builder
+		last    []byte
+		done    bool
+	}
+)
+
+// ChatCompletionJSONStream method is an API call to create a chat
+// completion w/ streamed object output.
+//
+// The returned stream decodes the partial JSON generated so far into
+// snapshots of T which are filled in progressively. The last value
+// received before io.EOF is the complete object decoded from the whole
+// response, which is validated against the schema of T first.
+func ChatCompletionJSONStream[T any](
+	ctx context.Context,
+	client *Client,
+	request ChatCompletionRequest,
+) (*JSONStream[T], error) {
+	format, err := jsonResponseFormat(new(T))
+	if err != nil {
+		return nil, err
+	}
+	request.ResponseFormat = format
+	stream, err := client.ChatCompletionStream(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONStream[T]{
+		stream: stream,
+		schema: &format.JSONSchema.Schema,
+	}, nil
+}
+
+// Recv receives the next snapshot of the object.
+//
+// A snapshot is only returned when it changed since the previous one.
+func (s *JSONStream[T]) Recv() (T, error) {
+	var zero T
+	for {
+		if s.done {
+			return zero, io.EOF
+		}
+		chunk, err := s.stream.Recv()
+		if errors.Is(err, io.EOF) {
+			s.done = true
+			return s.decodeFinal()
+		}
+		if err != nil {
+			return zero, err
+		}
+		s.acc.Add(chunk)
+		for _, choice := range chunk.Choices {
+			if choice.Index == 0 {
+				s.content.WriteString(choice.Delta.Content)
+			}
+		}
+		completed, ok := partialjson.Complete(
+			[]byte(extractJSON(s.content.String())),
+		)
+		if !ok || bytes.Equal(completed, s.last) {
+			continue
+		}
+		var snapshot T
+		if json.Unmarshal(completed, &snapshot) != nil {
+			continue
+		}
+		s.last = completed
+		return snapshot, nil
+	}
+}
+
+// All returns an iterator over the remaining snapshots of the object.
+//
+// The stream is closed when the iteration stops.
+func (s *JSONStream[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		defer s.Close()
+		for {
+			snapshot, err := s.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if !yield(snapshot, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Response returns the chat completion accumulated from the stream so far.
+func (s *JSONStream[T]) Response() ChatCompletionResponse {
+	return s.acc.Response()
+}
+
+// Close closes the underlying stream.
+func (s *JSONStream[T]) Close() error {
+	return s.stream.Close()
+}
+
+// decodeFinal validates the complete response against the schema and
+// decodes it into the final object.
+func (s *JSONStream[T]) decodeFinal() (T, error) {
+	var final T
+	content := []byte(extractJSON(s.content.String()))
+	err := s.schema.Validate(content)
+	if err != nil {
+		return final, err
+	}
+	err = json.Unmarshal(content, &final)
+	if err != nil {
+		return final, fmt.Errorf(
+			"error unmarshalling response (%s) to output: %w",
+			s.acc.Response().ID,
+			err,
+		)
+	}
+	return final, nil
+}
+
+// jsonResponseFormat returns the json schema response format reflected from
+// the type of the given value.
+func jsonResponseFormat(v any) (*ChatResponseFormat, error) {
+	schema, err := jsonschema.ReflectSchema(v)
+	if err != nil {
+		return nil, err
+	}
+	return &ChatResponseFormat{
+		JSONSchema: &JSONSchema{
+			Name:        schema.Title,
+			Description: schema.Description,
+			Schema:      *schema,
+			Strict:      true,
+		},
+		Type: FormatJSON,
+	}, nil
+}
+
+// extractJSON returns the JSON contained in a model's reply, removing a
+// surrounding markdown code fence and its language tag.
+func extractJSON(content string) string {
+	start := strings.Index(content, "```")
+	if start < 0 {
+		return content
+	}
+	content = strings.TrimLeftFunc(content[start+3:], unicode.IsLetter)
+	if end := strings.Index(content, "```"); end >= 0 {
+		content = content[:end]
+	}
+	return content
+}
@@ -0,0 +1,83 @@
+package groq_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/stretchr/testify/assert"
+)
+
+type streamedPerson struct {
+	Name    string   `json:"name" jsonschema:"title=name"`
+	Age     int      `json:"age"`
+	Hobbies []string `json:"hobbies"`
+}
+
+// TestChatCompletionJSONStream tests that partial JSON output is decoded
+// into progressively filled snapshots.
+func TestChatCompletionJSONStream(t *testing.T) {
+	a := assert.New(t)
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	deltas := []string{
+		"```json\n",
+		`{"name": "Ad`,
+		`a", "age": 3`,
+		`6, "hobbies": ["ma`,
+		`th", "poetry"]}`,
+		"\n```",
+	}
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		func(w http.ResponseWriter, r *http.Request) {
+			var req groq.ChatCompletionRequest
+			a.NoError(json.NewDecoder(r.Body).Decode(&req))
+			a.True(req.Stream)
+			a.NotNil(req.ResponseFormat)
+			a.NotNil(req.ResponseFormat.JSONSchema)
+			w.Header().Set("Content-Type", "text/event-stream")
+			var b strings.Builder
+			for _, delta := range deltas {
+				chunk, err := json.Marshal(groq.ChatCompletionStreamResponse{
+					ID: "chatcmpl-json",
+					Choices: []groq.ChatCompletionStreamChoice{{
+						Delta: groq.ChatCompletionStreamChoiceDelta{Content: delta},
+					}},
+				})
+				a.NoError(err)
+				b.WriteString("data: " + string(chunk) + "\n\n")
+			}
+			b.WriteString("data: [DONE]\n\n")
+			_, err := w.Write([]byte(b.String()))
+			a.NoError(err)
+		},
+	)
+	stream, err := groq.ChatCompletionJSONStream[streamedPerson](
+		context.Background(),
+		client,
+		groq.ChatCompletionRequest{
+			Model: groq.ModelLlama3370BVersatile,
+			Messages: []groq.ChatCompletionMessage{
+				{Role: groq.RoleUser, Content: "Describe Ada."},
+			},
+		},
+	)
+	a.NoError(err)
+	var snapshots []streamedPerson
+	for snapshot, err := range stream.All() {
+		a.NoError(err)
+		snapshots = append(snapshots, snapshot)
+	}
+	a.Equal([]streamedPerson{
+		{Name: "Ad"},
+		{Name: "Ada"},
+		{Name: "Ada", Age: 36, Hobbies: []string{"ma"}},
+		{Name: "Ada", Age: 36, Hobbies: []string{"math", "poetry"}},
+		{Name: "Ada", Age: 36, Hobbies: []string{"math", "poetry"}},
+	}, snapshots)
+	a.Equal("chatcmpl-json", stream.Response().ID)
+}
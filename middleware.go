@@ -0,0 +1,188 @@
+package groq
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type (
+	// Call is an operation of a client passed through its middleware.
+	Call struct {
+		// Operation is the name of the operation, such as
+		// "ChatCompletion", "Transcribe" or "ExecuteTool" for the tool
+		// calls run by an Agent.
+		Operation string
+		// Model is the model the operation was called with, if any.
+		//
+		// It is informational; middleware changing the model should
+		// change it in the request instead.
+		Model string
+		// Request is a pointer to the typed request of the operation, such
+		// as a *ChatCompletionRequest.
+		//
+		// Middleware may modify the request it points to before calling
+		// the next handler. For the extensions it is the *http.Request of
+		// the call.
+		Request any
+	}
+	// Handler handles a call and returns its typed response, such as a
+	// ChatCompletionResponse or a *ChatCompletionStream.
+	//
+	// For the extensions the response is the value the body of the http
+	// response was decoded into.
+	Handler func(ctx context.Context, call *Call) (any, error)
+	// Middleware wraps the handler of the calls of a client.
+	//
+	// A middleware may inspect or modify the call before calling next,
+	// inspect or replace its response and error afterwards, or return a
+	// response of the same type without calling next at all.
+	Middleware func(next Handler) Handler
+)
+
+// WithMiddleware appends middleware to the chain wrapping every operation
+// of the Groq client.
+//
+// The first middleware is the outermost one. Chat completions are passed
+// through the chain once per model of a fallback chain.
+func WithMiddleware(middleware ...Middleware) Opts {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// Chain returns a middleware applying the middleware in order, the first
+// being the outermost one.
+func Chain(middleware ...Middleware) Middleware {
+	return func(next Handler) Handler {
+		for i := len(middleware) - 1; i >= 0; i-- {
+			next = middleware[i](next)
+		}
+		return next
+	}
+}
+
+// Invoke passes a call through the middleware before sending it.
+//
+// It is used by the client and its extensions to wrap their operations
+// and returns an error if a middleware returns a response of another type
+// than T.
+func Invoke[T any](
+	ctx context.Context,
+	middleware []Middleware,
+	call *Call,
+	send func(ctx context.Context, call *Call) (T, error),
+) (response T, err error) {
+	if len(middleware) == 0 {
+		return send(ctx, call)
+	}
+	handler := Chain(middleware...)(func(ctx context.Context, call *Call) (any, error) {
+		return send(ctx, call)
+	})
+	res, err := handler(ctx, call)
+	if res == nil {
+		return response, err
+	}
+	response, ok := res.(T)
+	if !ok {
+		return response, fmt.Errorf(
+			"middleware returned %T for %s instead of %T",
+			res,
+			call.Operation,
+			response,
+		)
+	}
+	return response, err
+}
+
+// InvokeRequest passes an http request of an extension through the
+// middleware before sending it with send, which decodes the response into
+// v.
+//
+// The Request of the call is the http request and its response is v.
+func InvokeRequest(
+	middleware []Middleware,
+	operation string,
+	req *http.Request,
+	v any,
+	send func(req *http.Request, v any) error,
+) error {
+	_, err := Invoke(
+		req.Context(),
+		middleware,
+		&Call{Operation: operation, Request: req},
+		func(_ context.Context, call *Call) (any, error) {
+			req, ok := call.Request.(*http.Request)
+			if !ok || req == nil {
+				return nil, fmt.Errorf(
+					"request of %s is %T instead of *http.Request",
+					call.Operation,
+					call.Request,
+				)
+			}
+			return v, send(req, v)
+		},
+	)
+	return err
+}
+
+// LoggingMiddleware returns a middleware logging the operation, model,
+// duration and error of every call.
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (any, error) {
+			start := time.Now()
+			res, err := next(ctx, call)
+			attrs := []any{
+				"operation", call.Operation,
+				"model", call.Model,
+				"duration", time.Since(start),
+			}
+			if err != nil {
+				logger.ErrorContext(ctx, "groq call failed", append(attrs, "error", err)...)
+				return res, err
+			}
+			logger.DebugContext(ctx, "groq call", attrs...)
+			return res, err
+		}
+	}
+}
+
+// invoke passes an operation of the client through its middleware before
+// sending its typed request.
+func invoke[Request, Response any](
+	ctx context.Context,
+	c *Client,
+	operation, model string,
+	request Request,
+	send func(ctx context.Context, request Request) (Response, error),
+) (Response, error) {
+	return Invoke(
+		ctx,
+		c.middleware,
+		&Call{Operation: operation, Model: model, Request: &request},
+		func(ctx context.Context, call *Call) (response Response, err error) {
+			request, err := callRequest[Request](call)
+			if err != nil {
+				return response, err
+			}
+			return send(ctx, request)
+		},
+	)
+}
+
+// callRequest returns the typed request of a call made by the client.
+func callRequest[T any](call *Call) (request T, err error) {
+	r, ok := call.Request.(*T)
+	if !ok || r == nil {
+		return request, fmt.Errorf(
+			"request of %s is %T instead of %T",
+			call.Operation,
+			call.Request,
+			r,
+		)
+	}
+	return *r, nil
+}
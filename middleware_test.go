@@ -0,0 +1,144 @@
+package groq_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/conneroisu/groq-go/pkg/tools"
+	"github.com/stretchr/testify/assert"
+)
+
+// trace returns a middleware recording the calls passing through it.
+func trace(name string, calls *[]string) groq.Middleware {
+	return func(next groq.Handler) groq.Handler {
+		return func(ctx context.Context, call *groq.Call) (any, error) {
+			*calls = append(*calls, name+" "+call.Operation+" "+call.Model)
+			return next(ctx, call)
+		}
+	}
+}
+
+// TestMiddlewareChain tests the order of the middleware and that it can
+// modify typed requests and responses.
+func TestMiddlewareChain(t *testing.T) {
+	a := assert.New(t)
+	var calls []string
+	redact := func(next groq.Handler) groq.Handler {
+		return func(ctx context.Context, call *groq.Call) (any, error) {
+			request, ok := call.Request.(*groq.ChatCompletionRequest)
+			if !ok {
+				return next(ctx, call)
+			}
+			request.Messages[0].Content = "[redacted]"
+			res, err := next(ctx, call)
+			response := res.(groq.ChatCompletionResponse)
+			response.ID = "redacted"
+			return response, err
+		}
+	}
+	client, server, teardown := setupGroqTestServer(
+		groq.WithMiddleware(trace("a", &calls), trace("b", &calls), redact),
+	)
+	defer teardown()
+	var sent []string
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		var req groq.ChatCompletionRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		sent = append(sent, req.Messages[0].Content)
+		_ = json.NewEncoder(w).Encode(jsonReply("hello"))
+	})
+	messages := []groq.ChatCompletionMessage{{Role: groq.RoleUser, Content: "secret"}}
+	response, err := client.ChatCompletion(context.Background(), groq.ChatCompletionRequest{
+		Model:    groq.ModelLlama38B8192,
+		Messages: messages,
+	})
+	a.NoError(err)
+	a.Equal("redacted", response.ID)
+	a.Equal([]string{"[redacted]"}, sent)
+	a.Equal([]string{
+		"a ChatCompletion llama3-8b-8192",
+		"b ChatCompletion llama3-8b-8192",
+	}, calls)
+
+	calls = nil
+	_, err = client.ListModels(context.Background())
+	a.Error(err)
+	a.Equal([]string{"a ListModels ", "b ListModels "}, calls)
+}
+
+// TestMiddlewareShortCircuit tests that a middleware can answer a call
+// without sending it and that responses of the wrong type are rejected.
+func TestMiddlewareShortCircuit(t *testing.T) {
+	a := assert.New(t)
+	cached := func(next groq.Handler) groq.Handler {
+		return func(ctx context.Context, call *groq.Call) (any, error) {
+			if call.Operation == "ChatCompletion" {
+				return jsonReply("cached"), nil
+			}
+			return "not a response", nil
+		}
+	}
+	client, _, teardown := setupGroqTestServer(groq.WithMiddleware(cached))
+	defer teardown()
+	response, err := client.ChatCompletion(context.Background(), groq.ChatCompletionRequest{
+		Model: groq.ModelLlama38B8192,
+	})
+	a.NoError(err)
+	a.Equal("cached", response.Choices[0].Message.Content)
+	_, err = client.Embeddings(context.Background(), groq.EmbeddingRequest{
+		Model: "embedding-model",
+		Input: []string{"hi"},
+	})
+	a.ErrorContains(err, "middleware returned string for Embeddings")
+
+	sentinel := errors.New("blocked")
+	blocked, _, teardown := setupGroqTestServer(groq.WithMiddleware(
+		func(groq.Handler) groq.Handler {
+			return func(context.Context, *groq.Call) (any, error) {
+				return nil, sentinel
+			}
+		},
+	))
+	defer teardown()
+	_, err = blocked.Moderate(context.Background(), nil, groq.ModelLlamaGuard38B)
+	a.ErrorIs(err, sentinel)
+}
+
+// TestMiddlewareAgentTools tests that the tool calls of an agent pass
+// through the middleware of its client.
+func TestMiddlewareAgentTools(t *testing.T) {
+	a := assert.New(t)
+	var calls []string
+	client, server, teardown := setupGroqTestServer(groq.WithMiddleware(trace("a", &calls)))
+	defer teardown()
+	replies := []groq.ChatCompletionResponse{
+		toolCallsResponse(tools.ToolCall{
+			ID:       "call_1",
+			Type:     "function",
+			Function: tools.FunctionCall{Name: "weather", Arguments: `{"city":"Paris"}`},
+		}),
+		jsonReply("sunny"),
+	}
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(replies[0])
+		replies = replies[1:]
+	})
+	agent := groq.NewAgent(client, groq.WithToolHandler(
+		weatherTool,
+		func(context.Context, tools.ToolCall) (string, error) { return "sunny", nil },
+	))
+	_, err := agent.Run(context.Background(), groq.ChatCompletionRequest{
+		Model:    groq.ModelLlama38B8192,
+		Messages: []groq.ChatCompletionMessage{{Role: groq.RoleUser, Content: "weather?"}},
+	})
+	a.NoError(err)
+	a.Equal([]string{
+		"a ChatCompletion llama3-8b-8192",
+		"a ExecuteTool ",
+		"a ChatCompletion llama3-8b-8192",
+	}, calls)
+}
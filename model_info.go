@@ -0,0 +1,128 @@
+package groq
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+)
+
+const (
+	// ModelKindChat is the kind of chat models.
+	ModelKindChat ModelKind = "chat"
+	// ModelKindAudio is the kind of audio models.
+	ModelKindAudio ModelKind = "audio"
+	// ModelKindSpeech is the kind of text to speech models.
+	ModelKindSpeech ModelKind = "speech"
+	// ModelKindModeration is the kind of moderation models.
+	ModelKindModeration ModelKind = "moderation"
+
+	// CapabilityChat is the capability of answering chat completions.
+	CapabilityChat ModelCapability = "chat"
+	// CapabilityStreaming is the capability of streaming chat completions.
+	CapabilityStreaming ModelCapability = "streaming"
+	// CapabilityJSON is the capability of answering in JSON mode.
+	CapabilityJSON ModelCapability = "json"
+	// CapabilityTools is the capability of calling tools.
+	CapabilityTools ModelCapability = "tools"
+	// CapabilityVision is the capability of reading images.
+	CapabilityVision ModelCapability = "vision"
+	// CapabilityTranscription is the capability of transcribing audio.
+	CapabilityTranscription ModelCapability = "transcription"
+	// CapabilityTranslation is the capability of translating audio to
+	// English.
+	CapabilityTranslation ModelCapability = "translation"
+	// CapabilitySpeech is the capability of synthesizing speech.
+	CapabilitySpeech ModelCapability = "speech"
+	// CapabilityModeration is the capability of moderating messages.
+	CapabilityModeration ModelCapability = "moderation"
+)
+
+type (
+	// ModelKind is the kind of a model.
+	ModelKind string
+	// ModelCapability is a capability of a model.
+	ModelCapability string
+	// ModelInfo is the metadata of a model present on the groq api.
+	ModelInfo struct {
+		// ID is the id of the model.
+		ID Model
+		// Kind is the kind of the model.
+		Kind ModelKind
+		// ContextWindow is the context window of the model in tokens.
+		ContextWindow int
+		// Owner is the organization that created the model.
+		Owner string
+		// Active is whether the model is served by the groq api.
+		Active bool
+		// Capabilities are the capabilities of the model.
+		//
+		// Chat models have the tools and vision capabilities only when
+		// groq documents them for the model, see cmd/generate-models.
+		Capabilities []ModelCapability
+	}
+)
+
+// ModelInfoFor returns the metadata of a model from the Models registry.
+//
+// It returns false if the model is not in the registry.
+func ModelInfoFor[
+	M ChatModel | AudioModel | SpeechModel | ModerationModel | Model,
+](model M) (ModelInfo, bool) {
+	info, ok := Models[Model(model)]
+	return info, ok
+}
+
+// HasCapability returns whether the model has all of the capabilities.
+func (m ModelInfo) HasCapability(capabilities ...ModelCapability) bool {
+	for _, capability := range capabilities {
+		if !slices.Contains(m.Capabilities, capability) {
+			return false
+		}
+	}
+	return true
+}
+
+// ModelForContext returns the active chat model of the registry with the
+// smallest context window that fits the tokens and has all of the
+// capabilities.
+//
+// Models with equal context windows are ordered by id. It returns false if
+// no model fits.
+func ModelForContext(
+	tokens int,
+	capabilities ...ModelCapability,
+) (ChatModel, bool) {
+	var best ModelInfo
+	for _, info := range Models {
+		if info.Kind != ModelKindChat ||
+			!info.Active ||
+			info.ContextWindow < tokens ||
+			!info.HasCapability(capabilities...) {
+			continue
+		}
+		if best.ID == "" ||
+			info.ContextWindow < best.ContextWindow ||
+			info.ContextWindow == best.ContextWindow &&
+				strings.Compare(string(info.ID), string(best.ID)) < 0 {
+			best = info
+		}
+	}
+	return ChatModel(best.ID), best.ID != ""
+}
+
+// checkContextWindow returns a groqerr.ErrContextWindowExceeded if the
+// MaxTokens of a request exceed the context window of its model.
+//
+// Models missing from the registry are not checked.
+func checkContextWindow(request ChatCompletionRequest) error {
+	info, ok := ModelInfoFor(request.Model)
+	if !ok || request.MaxTokens <= info.ContextWindow {
+		return nil
+	}
+	return groqerr.ErrContextWindowExceeded{
+		Model:         string(request.Model),
+		Tokens:        request.MaxTokens,
+		ContextWindow: info.ContextWindow,
+	}
+}
@@ -0,0 +1,91 @@
+package groq_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestModelInfoFor tests looking up models of every kind in the registry.
+func TestModelInfoFor(t *testing.T) {
+	a := assert.New(t)
+	info, ok := groq.ModelInfoFor(groq.ModelLlama318BInstant)
+	a.True(ok)
+	a.Equal(groq.ModelKindChat, info.Kind)
+	a.Equal(131072, info.ContextWindow)
+	a.Equal("Meta", info.Owner)
+	a.True(info.HasCapability(groq.CapabilityChat, groq.CapabilityTools))
+	a.False(info.HasCapability(groq.CapabilityVision))
+
+	info, ok = groq.ModelInfoFor(groq.ModelLlama3211BVisionPreview)
+	a.True(ok)
+	a.True(info.HasCapability(groq.CapabilityVision))
+
+	info, ok = groq.ModelInfoFor(groq.ModelWhisperLargeV3Turbo)
+	a.True(ok)
+	a.Equal(groq.ModelKindAudio, info.Kind)
+	a.True(info.HasCapability(groq.CapabilityTranscription))
+	a.False(info.HasCapability(groq.CapabilityTranslation))
+
+	info, ok = groq.ModelInfoFor(groq.ModelPlayaiTts)
+	a.True(ok)
+	a.Equal(groq.ModelKindSpeech, info.Kind)
+	a.True(info.HasCapability(groq.CapabilitySpeech))
+
+	info, ok = groq.ModelInfoFor(groq.ModelLlamaGuard38B)
+	a.True(ok)
+	a.Equal(groq.ModelKindModeration, info.Kind)
+
+	_, ok = groq.ModelInfoFor(groq.ChatModel("not-a-model"))
+	a.False(ok)
+
+	for id, info := range groq.Models {
+		a.Equal(id, info.ID)
+		a.Positive(info.ContextWindow, id)
+	}
+}
+
+// TestModelForContext tests picking the smallest chat model fitting a
+// number of tokens.
+func TestModelForContext(t *testing.T) {
+	a := assert.New(t)
+	model, ok := groq.ModelForContext(100000)
+	a.True(ok)
+	a.Equal(groq.ModelLlama318BInstant, model)
+	// the inactive llama-3.1-70b-versatile is skipped for the next model
+	// of the same context window
+	model, ok = groq.ModelForContext(20000, groq.CapabilityTools)
+	a.True(ok)
+	a.Equal(groq.ModelLlama3370BVersatile, model)
+	_, ok = groq.ModelForContext(9000, groq.CapabilityVision)
+	a.False(ok)
+	_, ok = groq.ModelForContext(1 << 30)
+	a.False(ok)
+}
+
+// TestChatCompletionMaxTokensExceedWindow tests that requests asking for
+// more tokens than the model's context window are rejected before being
+// sent.
+func TestChatCompletionMaxTokensExceedWindow(t *testing.T) {
+	a := assert.New(t)
+	client, requests, teardown := setupAgentTestServer(t, jsonReply("hi"))
+	defer teardown()
+	_, err := client.ChatCompletion(context.Background(), groq.ChatCompletionRequest{
+		Model:     groq.ModelLlama38B8192,
+		Messages:  []groq.ChatCompletionMessage{{Role: groq.RoleUser, Content: "hi"}},
+		MaxTokens: 10000,
+	})
+	var windowErr groqerr.ErrContextWindowExceeded
+	a.ErrorAs(err, &windowErr)
+	a.Equal(8192, windowErr.ContextWindow)
+	a.Equal(10000, windowErr.Tokens)
+	_, err = client.ChatCompletionStream(context.Background(), groq.ChatCompletionRequest{
+		Model:     groq.ModelLlama38B8192,
+		MaxTokens: 10000,
+	})
+	a.ErrorAs(err, &windowErr)
+	a.Empty(*requests)
+}
@@ -0,0 +1,238 @@
+package groq
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/conneroisu/groq-go/pkg/builders"
+)
+
+const (
+	modelsSuffix endpoint = "/models"
+	// moderationModelID is the id of the moderation model, which can not be
+	// told apart from chat models by its context window.
+	moderationModelID = "llama-guard-3-8b"
+	// minChatContextWindow is the smallest context window of chat models.
+	minChatContextWindow = 1024
+)
+
+type (
+	// ModelDetails is the metadata of a model returned by the groq api.
+	ModelDetails struct {
+		// ID is the id of the model.
+		ID Model `json:"id"`
+		// Object is the object type, always "model".
+		Object string `json:"object"`
+		// Created is the unix timestamp of the creation of the model.
+		Created int64 `json:"created"`
+		// OwnedBy is the organization that created the model.
+		OwnedBy string `json:"owned_by"`
+		// Active is whether the model is served by the groq api.
+		Active bool `json:"active"`
+		// ContextWindow is the context window of the model in tokens.
+		ContextWindow int `json:"context_window"`
+
+		header http.Header
+	}
+	// ModelList is the list of models returned by the groq api.
+	ModelList struct {
+		// Object is the object type, always "list".
+		Object string `json:"object"`
+		// Data is the list of models.
+		Data []ModelDetails `json:"data"`
+
+		header http.Header
+	}
+	// ModelCatalog is a cache of the models served by the groq api merged
+	// with the Models registry.
+	//
+	// It is safe for concurrent use.
+	ModelCatalog struct {
+		client    *Client
+		ttl       time.Duration
+		mu        sync.Mutex
+		models    map[Model]ModelInfo
+		refreshed time.Time
+	}
+)
+
+// SetHeader sets the header of the response.
+func (r *ModelDetails) SetHeader(h http.Header) { r.header = h }
+
+// SetHeader sets the header of the response.
+func (r *ModelList) SetHeader(h http.Header) { r.header = h }
+
+// ListModels method is an API call to list the models served by the groq
+// api.
+func (c *Client) ListModels(ctx context.Context) (response ModelList, err error) {
+	return invoke(ctx, c, "ListModels", "", struct{}{}, c.listModels)
+}
+
+// listModels sends a request listing the models.
+func (c *Client) listModels(
+	ctx context.Context,
+	_ struct{},
+) (response ModelList, err error) {
+	req, err := builders.NewRequest(
+		ctx,
+		c.header,
+		http.MethodGet,
+		c.fullURL(modelsSuffix),
+	)
+	if err != nil {
+		return
+	}
+	err = c.sendRequest(req, &response)
+	return
+}
+
+// GetModel method is an API call to get the metadata of a model served by
+// the groq api.
+func (c *Client) GetModel(
+	ctx context.Context,
+	id Model,
+) (response ModelDetails, err error) {
+	return invoke(ctx, c, "GetModel", string(id), id, c.getModel)
+}
+
+// getModel sends a request getting a model.
+func (c *Client) getModel(
+	ctx context.Context,
+	id Model,
+) (response ModelDetails, err error) {
+	req, err := builders.NewRequest(
+		ctx,
+		c.header,
+		http.MethodGet,
+		c.fullURL(modelsSuffix)+"/"+url.PathEscape(string(id)),
+	)
+	if err != nil {
+		return
+	}
+	err = c.sendRequest(req, &response)
+	return
+}
+
+// Info returns the metadata of the model merged with its entry in the
+// Models registry.
+//
+// The context window, owner and availability are taken from the api while
+// the kind and capabilities are taken from the registry. Models missing from
+// the registry get their kind guessed from their context window and no
+// capabilities.
+func (d ModelDetails) Info() ModelInfo {
+	info, ok := Models[d.ID]
+	if !ok {
+		info = ModelInfo{ID: d.ID, Kind: guessModelKind(d)}
+	}
+	info.ContextWindow = d.ContextWindow
+	info.Owner = d.OwnedBy
+	info.Active = d.Active
+	info.Capabilities = slices.Clone(info.Capabilities)
+	return info
+}
+
+// guessModelKind guesses the kind of a model missing from the registry the
+// same way cmd/generate-models categorizes models.
+func guessModelKind(d ModelDetails) ModelKind {
+	switch {
+	case d.ID == moderationModelID:
+		return ModelKindModeration
+	case strings.Contains(string(d.ID), "tts"):
+		return ModelKindSpeech
+	case d.ContextWindow >= minChatContextWindow:
+		return ModelKindChat
+	default:
+		return ModelKindAudio
+	}
+}
+
+// NewModelCatalog creates a catalog of the models served by the groq api
+// refreshing its cache once it is older than ttl.
+//
+// A ttl of zero refreshes the cache only when Refresh is called or the
+// cache is empty.
+func NewModelCatalog(client *Client, ttl time.Duration) *ModelCatalog {
+	return &ModelCatalog{client: client, ttl: ttl}
+}
+
+// Refresh lists the models served by the groq api and merges them with the
+// Models registry.
+//
+// Models of the registry no longer listed by the api are kept as inactive.
+func (c *ModelCatalog) Refresh(ctx context.Context) error {
+	list, err := c.client.ListModels(ctx)
+	if err != nil {
+		return err
+	}
+	models := make(map[Model]ModelInfo, len(Models)+len(list.Data))
+	for id, info := range Models {
+		info.Active = false
+		info.Capabilities = slices.Clone(info.Capabilities)
+		models[id] = info
+	}
+	for _, d := range list.Data {
+		models[d.ID] = d.Info()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.models = models
+	c.refreshed = time.Now()
+	return nil
+}
+
+// Models returns a copy of the merged models, refreshing them if the cache
+// is empty or stale.
+func (c *ModelCatalog) Models(ctx context.Context) (map[Model]ModelInfo, error) {
+	c.mu.Lock()
+	stale := c.models == nil ||
+		c.ttl > 0 && time.Since(c.refreshed) > c.ttl
+	c.mu.Unlock()
+	if stale {
+		if err := c.Refresh(ctx); err != nil {
+			return nil, err
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return maps.Clone(c.models), nil
+}
+
+// Lookup returns the merged metadata of a model.
+//
+// It returns false if the model is neither served by the groq api nor in
+// the Models registry.
+func (c *ModelCatalog) Lookup(
+	ctx context.Context,
+	model Model,
+) (ModelInfo, bool, error) {
+	models, err := c.Models(ctx)
+	if err != nil {
+		return ModelInfo{}, false, err
+	}
+	info, ok := models[model]
+	return info, ok, nil
+}
+
+// Deprecated returns the sorted ids of the models of the Models registry
+// that are no longer active on the groq api.
+func (c *ModelCatalog) Deprecated(ctx context.Context) ([]Model, error) {
+	models, err := c.Models(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var deprecated []Model
+	for id := range Models {
+		if !models[id].Active {
+			deprecated = append(deprecated, id)
+		}
+	}
+	slices.Sort(deprecated)
+	return deprecated, nil
+}
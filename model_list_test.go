@@ -0,0 +1,108 @@
+package groq_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// liveModels are the models listed by the test server.
+var liveModels = []groq.ModelDetails{
+	{ID: "gemma2-9b-it", Object: "model", OwnedBy: "Google", Active: true, ContextWindow: 8192},
+	{ID: "llama-3.1-8b-instant", Object: "model", OwnedBy: "Meta", Active: true, ContextWindow: 131072},
+	{ID: "whisper-large-v3", Object: "model", OwnedBy: "OpenAI", Active: true, ContextWindow: 448},
+	{ID: "new-model", Object: "model", OwnedBy: "Groq", Active: true, ContextWindow: 16384},
+}
+
+// setupModelsTestServer sets up a test server listing the live models and
+// returns the number of list requests it received.
+func setupModelsTestServer(t *testing.T) (*groq.Client, *atomic.Int32, func()) {
+	t.Helper()
+	client, server, teardown := setupGroqTestServer()
+	var lists atomic.Int32
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, r *http.Request) {
+		lists.Add(1)
+		_ = json.NewEncoder(w).Encode(groq.ModelList{Object: "list", Data: liveModels})
+	})
+	server.RegisterHandler("/v1/models/*", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/v1/models/")
+		for _, m := range liveModels {
+			if string(m.ID) == id {
+				_ = json.NewEncoder(w).Encode(m)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"message":"The model does not exist","type":"invalid_request_error","code":"model_not_found"}}`))
+	})
+	return client, &lists, teardown
+}
+
+// TestListModels tests listing and getting models.
+func TestListModels(t *testing.T) {
+	a := assert.New(t)
+	client, _, teardown := setupModelsTestServer(t)
+	defer teardown()
+	ctx := context.Background()
+	list, err := client.ListModels(ctx)
+	require.NoError(t, err)
+	a.Len(list.Data, len(liveModels))
+	a.Equal(groq.Model("gemma2-9b-it"), list.Data[0].ID)
+
+	model, err := client.GetModel(ctx, groq.Model(groq.ModelLlama318BInstant))
+	a.NoError(err)
+	a.Equal(131072, model.ContextWindow)
+	info := model.Info()
+	a.Equal(groq.ModelKindChat, info.Kind)
+	a.True(info.HasCapability(groq.CapabilityTools))
+
+	_, err = client.GetModel(ctx, groq.Model(groq.ModelGemma7BIt))
+	a.Error(err)
+}
+
+// TestModelCatalog tests merging the live models with the registry and
+// detecting deprecated models.
+func TestModelCatalog(t *testing.T) {
+	a := assert.New(t)
+	client, lists, teardown := setupModelsTestServer(t)
+	defer teardown()
+	ctx := context.Background()
+	catalog := groq.NewModelCatalog(client, time.Hour)
+
+	info, ok, err := catalog.Lookup(ctx, groq.Model(groq.ModelGemma7BIt))
+	a.NoError(err)
+	a.True(ok)
+	a.False(info.Active)
+
+	info, ok, err = catalog.Lookup(ctx, "new-model")
+	a.NoError(err)
+	a.True(ok)
+	a.True(info.Active)
+	a.Equal(groq.ModelKindChat, info.Kind)
+	a.Equal(16384, info.ContextWindow)
+
+	deprecated, err := catalog.Deprecated(ctx)
+	a.NoError(err)
+	a.Contains(deprecated, groq.Model(groq.ModelGemma7BIt))
+	a.NotContains(deprecated, groq.Model(groq.ModelGemma29BIt))
+	a.NotContains(deprecated, groq.Model(groq.ModelWhisperLargeV3))
+	a.Equal(int32(1), lists.Load())
+
+	a.NoError(catalog.Refresh(ctx))
+	a.Equal(int32(2), lists.Load())
+	// the registry is not modified by the catalog
+	a.False(groq.Models[groq.Model(groq.ModelGemma7BIt)].Active)
+	info, ok, err = catalog.Lookup(ctx, groq.Model(groq.ModelLlama3370BVersatile))
+	a.NoError(err)
+	a.True(ok)
+	a.False(info.Active)
+	a.True(groq.Models[groq.Model(groq.ModelLlama3370BVersatile)].Active)
+}
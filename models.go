@@ -1,8 +1,8 @@
 // Code generated by groq-modeler DO NOT EDIT.
 //
-// Created at: 2024-12-17 10:47:56
+// Created at: 2026-10-18 03:45:23
 //
-// groq-modeler Version 1.1.2
+// groq-modeler Version 1.2.0
 
 package groq
 
@@ -18,6 +18,9 @@ type (
 
 	// AudioModel is the type for audio models present on the groq api.
 	AudioModel Model
+
+	// SpeechModel is the type for text to speech models present on the groq api.
+	SpeechModel Model
 )
 
 var (
@@ -216,6 +219,26 @@ var (
 	//	- CreateTranscription
 	// 	- CreateTranslation
 	ModelWhisperLargeV3Turbo AudioModel = "whisper-large-v3-turbo"
+	// ModelPlayaiTts is an AI text to speech model.
+	//
+	// It is created/provided by PlayAI.
+	//
+	// It has 8192 context window.
+	//
+	// It can be used with the following client methods:
+	//	- Speech
+	// 	- SpeechToFile
+	ModelPlayaiTts SpeechModel = "playai-tts"
+	// ModelPlayaiTtsArabic is an AI text to speech model.
+	//
+	// It is created/provided by PlayAI.
+	//
+	// It has 8192 context window.
+	//
+	// It can be used with the following client methods:
+	//	- Speech
+	// 	- SpeechToFile
+	ModelPlayaiTtsArabic SpeechModel = "playai-tts-arabic"
 	// ModelLlamaGuard38B is an AI moderation model.
 	//
 	// It is created/provided by Meta.
@@ -226,3 +249,258 @@ var (
 	//	- Moderate
 	ModelLlamaGuard38B ModerationModel = "llama-guard-3-8b"
 )
+
+// Models is the registry of the models present on the groq api when the
+// library was generated keyed by their ids.
+var Models = map[Model]ModelInfo{
+	"gemma2-9b-it": {
+		ID:            "gemma2-9b-it",
+		Kind:          ModelKindChat,
+		ContextWindow: 8192,
+		Owner:         "Google",
+		Active:        true,
+		Capabilities: []ModelCapability{
+			CapabilityChat,
+			CapabilityStreaming,
+			CapabilityJSON,
+			CapabilityTools,
+		},
+	},
+	"gemma-7b-it": {
+		ID:            "gemma-7b-it",
+		Kind:          ModelKindChat,
+		ContextWindow: 8192,
+		Owner:         "Google",
+		Active:        false,
+		Capabilities: []ModelCapability{
+			CapabilityChat,
+			CapabilityStreaming,
+			CapabilityJSON,
+		},
+	},
+	"llama-3.1-70b-versatile": {
+		ID:            "llama-3.1-70b-versatile",
+		Kind:          ModelKindChat,
+		ContextWindow: 32768,
+		Owner:         "Meta",
+		Active:        false,
+		Capabilities: []ModelCapability{
+			CapabilityChat,
+			CapabilityStreaming,
+			CapabilityJSON,
+			CapabilityTools,
+		},
+	},
+	"llama-3.1-8b-instant": {
+		ID:            "llama-3.1-8b-instant",
+		Kind:          ModelKindChat,
+		ContextWindow: 131072,
+		Owner:         "Meta",
+		Active:        true,
+		Capabilities: []ModelCapability{
+			CapabilityChat,
+			CapabilityStreaming,
+			CapabilityJSON,
+			CapabilityTools,
+		},
+	},
+	"llama-3.2-11b-vision-preview": {
+		ID:            "llama-3.2-11b-vision-preview",
+		Kind:          ModelKindChat,
+		ContextWindow: 8192,
+		Owner:         "Meta",
+		Active:        false,
+		Capabilities: []ModelCapability{
+			CapabilityChat,
+			CapabilityStreaming,
+			CapabilityJSON,
+			CapabilityTools,
+			CapabilityVision,
+		},
+	},
+	"llama-3.2-1b-preview": {
+		ID:            "llama-3.2-1b-preview",
+		Kind:          ModelKindChat,
+		ContextWindow: 8192,
+		Owner:         "Meta",
+		Active:        false,
+		Capabilities: []ModelCapability{
+			CapabilityChat,
+			CapabilityStreaming,
+			CapabilityJSON,
+			CapabilityTools,
+		},
+	},
+	"llama-3.2-3b-preview": {
+		ID:            "llama-3.2-3b-preview",
+		Kind:          ModelKindChat,
+		ContextWindow: 8192,
+		Owner:         "Meta",
+		Active:        false,
+		Capabilities: []ModelCapability{
+			CapabilityChat,
+			CapabilityStreaming,
+			CapabilityJSON,
+			CapabilityTools,
+		},
+	},
+	"llama-3.2-90b-vision-preview": {
+		ID:            "llama-3.2-90b-vision-preview",
+		Kind:          ModelKindChat,
+		ContextWindow: 8192,
+		Owner:         "Meta",
+		Active:        false,
+		Capabilities: []ModelCapability{
+			CapabilityChat,
+			CapabilityStreaming,
+			CapabilityJSON,
+			CapabilityTools,
+			CapabilityVision,
+		},
+	},
+	"llama-3.3-70b-specdec": {
+		ID:            "llama-3.3-70b-specdec",
+		Kind:          ModelKindChat,
+		ContextWindow: 8192,
+		Owner:         "Meta",
+		Active:        false,
+		Capabilities: []ModelCapability{
+			CapabilityChat,
+			CapabilityStreaming,
+			CapabilityJSON,
+		},
+	},
+	"llama-3.3-70b-versatile": {
+		ID:            "llama-3.3-70b-versatile",
+		Kind:          ModelKindChat,
+		ContextWindow: 32768,
+		Owner:         "Meta",
+		Active:        true,
+		Capabilities: []ModelCapability{
+			CapabilityChat,
+			CapabilityStreaming,
+			CapabilityJSON,
+			CapabilityTools,
+		},
+	},
+	"llama3-70b-8192": {
+		ID:            "llama3-70b-8192",
+		Kind:          ModelKindChat,
+		ContextWindow: 8192,
+		Owner:         "Meta",
+		Active:        true,
+		Capabilities: []ModelCapability{
+			CapabilityChat,
+			CapabilityStreaming,
+			CapabilityJSON,
+			CapabilityTools,
+		},
+	},
+	"llama3-8b-8192": {
+		ID:            "llama3-8b-8192",
+		Kind:          ModelKindChat,
+		ContextWindow: 8192,
+		Owner:         "Meta",
+		Active:        true,
+		Capabilities: []ModelCapability{
+			CapabilityChat,
+			CapabilityStreaming,
+			CapabilityJSON,
+			CapabilityTools,
+		},
+	},
+	"llama3-groq-70b-8192-tool-use-preview": {
+		ID:            "llama3-groq-70b-8192-tool-use-preview",
+		Kind:          ModelKindChat,
+		ContextWindow: 8192,
+		Owner:         "Groq",
+		Active:        false,
+		Capabilities: []ModelCapability{
+			CapabilityChat,
+			CapabilityStreaming,
+			CapabilityJSON,
+			CapabilityTools,
+		},
+	},
+	"llama3-groq-8b-8192-tool-use-preview": {
+		ID:            "llama3-groq-8b-8192-tool-use-preview",
+		Kind:          ModelKindChat,
+		ContextWindow: 8192,
+		Owner:         "Groq",
+		Active:        false,
+		Capabilities: []ModelCapability{
+			CapabilityChat,
+			CapabilityStreaming,
+			CapabilityJSON,
+			CapabilityTools,
+		},
+	},
+	"mixtral-8x7b-32768": {
+		ID:            "mixtral-8x7b-32768",
+		Kind:          ModelKindChat,
+		ContextWindow: 32768,
+		Owner:         "Mistral AI",
+		Active:        false,
+		Capabilities: []ModelCapability{
+			CapabilityChat,
+			CapabilityStreaming,
+			CapabilityJSON,
+			CapabilityTools,
+		},
+	},
+	"distil-whisper-large-v3-en": {
+		ID:            "distil-whisper-large-v3-en",
+		Kind:          ModelKindAudio,
+		ContextWindow: 448,
+		Owner:         "Hugging Face",
+		Active:        true,
+		Capabilities: []ModelCapability{
+			CapabilityTranscription,
+		},
+	},
+	"whisper-large-v3": {
+		ID:            "whisper-large-v3",
+		Kind:          ModelKindAudio,
+		ContextWindow: 448,
+		Owner:         "OpenAI",
+		Active:        true,
+		Capabilities: []ModelCapability{
+			CapabilityTranscription,
+			CapabilityTranslation,
+		},
+	},
+	"whisper-large-v3-turbo": {
+		ID:            "whisper-large-v3-turbo",
+		Kind:          ModelKindAudio,
+		ContextWindow: 448,
+		Owner:         "OpenAI",
+		Active:        true,
+		Capabilities: []ModelCapability{
+			CapabilityTranscription,
+		},
+	},
+	"playai-tts": {
+		ID:            "playai-tts",
+		Kind:          ModelKindSpeech,
+		ContextWindow: 8192,
+		Owner:         "PlayAI",
+		Active:        true,
+		Capabilities:  []ModelCapability{CapabilitySpeech},
+	},
+	"playai-tts-arabic": {
+		ID:            "playai-tts-arabic",
+		Kind:          ModelKindSpeech,
+		ContextWindow: 8192,
+		Owner:         "PlayAI",
+		Active:        true,
+		Capabilities:  []ModelCapability{CapabilitySpeech},
+	},
+	"llama-guard-3-8b": {
+		ID:            "llama-guard-3-8b",
+		Kind:          ModelKindModeration,
+		ContextWindow: 8192,
+		Owner:         "Meta",
+		Active:        true,
+		Capabilities:  []ModelCapability{CapabilityModeration},
+	},
+}
@@ -1,8 +1,8 @@
 // Code generated by groq-modeler DO NOT EDIT.
 //
-// Created at: 2024-12-17 10:47:56
+// Created at: 2026-10-18 03:45:23
 //
-// groq-modeler Version 1.1.2
+// groq-modeler Version 1.2.0
 
 package groq_test
 
@@ -1,6 +1,10 @@
 package groqerr
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 type (
 	// ErrContentFieldsMisused is an error that occurs when both Content and
@@ -44,3 +48,193 @@ func (e *ErrRequest) Unwrap() error {
 func (e ErrToolNotFound) Error() string {
 	return fmt.Sprintf("tool %s not found", e.ToolName)
 }
+
+type (
+	// ErrRateLimited is returned by the client side rate limiter when a
+	// request would exceed the configured limits and the limiter is set
+	// to fail fast.
+	ErrRateLimited struct {
+		// Model is the model whose limits would be exceeded.
+		Model string
+		// RetryAfter is the time until the request could be sent.
+		RetryAfter time.Duration
+	}
+)
+
+// Error implements the error interface.
+func (e ErrRateLimited) Error() string {
+	return fmt.Sprintf(
+		"rate limit for model %s exceeded, retry after %s",
+		e.Model,
+		e.RetryAfter,
+	)
+}
+
+type (
+	// ErrAgentMaxIterations is returned when an agent run reached its
+	// maximum number of chat completions while the model kept calling
+	// tools.
+	ErrAgentMaxIterations struct {
+		// Iterations is the maximum number of iterations of the run.
+		Iterations int
+	}
+	// ErrAgentTokenBudget is returned when an agent run spent its token
+	// budget before the model stopped calling tools.
+	ErrAgentTokenBudget struct {
+		// Budget is the token budget of the run.
+		Budget int
+		// Used is the number of tokens used by the run.
+		Used int
+	}
+)
+
+// Error implements the error interface.
+func (e ErrAgentMaxIterations) Error() string {
+	return fmt.Sprintf("agent reached max iterations (%d)", e.Iterations)
+}
+
+// Error implements the error interface.
+func (e ErrAgentTokenBudget) Error() string {
+	return fmt.Sprintf(
+		"agent exceeded token budget: used %d of %d tokens",
+		e.Used,
+		e.Budget,
+	)
+}
+
+type (
+	// ErrToolArguments is returned when the arguments of a tool call can
+	// not be decoded into the arguments of the tool or are invalid.
+	ErrToolArguments struct {
+		// ToolName is the name of the called tool.
+		ToolName string
+		// Err is the decoding or validation error.
+		Err error
+	}
+)
+
+// Error implements the error interface.
+func (e ErrToolArguments) Error() string {
+	return fmt.Sprintf("invalid arguments for tool %s: %s", e.ToolName, e.Err)
+}
+
+// Unwrap unwraps the error.
+func (e ErrToolArguments) Unwrap() error {
+	return e.Err
+}
+
+type (
+	// ErrSchemaValidation is returned when a JSON value does not match the
+	// JSON schema it was validated against.
+	ErrSchemaValidation struct {
+		// Violations are every violation of the schema found in the value.
+		Violations []SchemaViolation
+	}
+	// SchemaViolation is a single violation of a JSON schema.
+	SchemaViolation struct {
+		// Path is the path of the violating value, such as
+		// "$.people[0].age".
+		Path string
+		// Message describes the violation.
+		Message string
+	}
+)
+
+// Error implements the error interface.
+func (e ErrSchemaValidation) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.String()
+	}
+	return fmt.Sprintf(
+		"value does not match schema: %s",
+		strings.Join(msgs, "; "),
+	)
+}
+
+// String returns the violation as "<path>: <message>".
+func (v SchemaViolation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+type (
+	// ErrContextWindowExceeded is returned when a prompt and its reply do
+	// not fit in the context window of a model.
+	ErrContextWindowExceeded struct {
+		// Model is the model whose context window is exceeded.
+		Model string
+		// Tokens is the number of tokens the prompt and reply need.
+		Tokens int
+		// ContextWindow is the context window of the model.
+		ContextWindow int
+	}
+)
+
+// Error implements the error interface.
+func (e ErrContextWindowExceeded) Error() string {
+	return fmt.Sprintf(
+		"%d tokens exceed the %d token context window of model %s",
+		e.Tokens,
+		e.ContextWindow,
+		e.Model,
+	)
+}
+
+type (
+	// ErrVisionUnsupported is returned when a request with image parts is
+	// sent to a model without vision.
+	ErrVisionUnsupported struct {
+		// Model is the model of the request.
+		Model string
+	}
+	// ErrTooManyImages is returned when a request has more image parts
+	// than a vision model accepts.
+	ErrTooManyImages struct {
+		// Count is the number of image parts of the request.
+		Count int
+		// Max is the number of image parts accepted per request.
+		Max int
+	}
+	// ErrUnsupportedImage is returned when an image is not of a format
+	// accepted by the vision models.
+	ErrUnsupportedImage struct {
+		// MIMEType is the sniffed mime type of the image.
+		MIMEType string
+	}
+	// ErrImageTooLarge is returned when an image can not be downscaled to
+	// fit the size limit of the vision models.
+	ErrImageTooLarge struct {
+		// Size is the size in bytes of the smallest encoding tried.
+		Size int
+		// Limit is the size limit in bytes.
+		Limit int
+	}
+)
+
+// Error implements the error interface.
+func (e ErrVisionUnsupported) Error() string {
+	return fmt.Sprintf("model %s does not support image inputs", e.Model)
+}
+
+// Error implements the error interface.
+func (e ErrTooManyImages) Error() string {
+	return fmt.Sprintf(
+		"%d images exceed the limit of %d images per request",
+		e.Count,
+		e.Max,
+	)
+}
+
+// Error implements the error interface.
+func (e ErrUnsupportedImage) Error() string {
+	return fmt.Sprintf("unsupported image type %s", e.MIMEType)
+}
+
+// Error implements the error interface.
+func (e ErrImageTooLarge) Error() string {
+	return fmt.Sprintf(
+		"image of %d bytes exceeds the limit of %d bytes",
+		e.Size,
+		e.Limit,
+	)
+}
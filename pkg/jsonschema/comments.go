@@ -0,0 +1,159 @@
+package jsonschema
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// AddGoComments parses the Go source files of the package tree rooted at
+// dir and adds the doc comments of its types and struct fields to the
+// reflector's CommentMap.
+//
+// pkgPath is the import path of the package in dir. Sub directories are
+// added with their import paths relative to it. Existing entries of the
+// CommentMap are overwritten.
+func (r *Reflector) AddGoComments(pkgPath, dir string) error {
+	comments, err := ExtractGoComments(pkgPath, dir)
+	if err != nil {
+		return err
+	}
+	if r.CommentMap == nil {
+		r.CommentMap = make(map[string]string, len(comments))
+	}
+	for k, v := range comments {
+		r.CommentMap[k] = v
+	}
+	return nil
+}
+
+// ExtractGoComments parses the Go source files of the package tree rooted
+// at dir and returns the doc comments of its types and struct fields keyed
+// as expected by Reflector.CommentMap.
+//
+// Test files and directories named testdata or starting with "." or "_"
+// are skipped. A field without a doc comment uses its line comment.
+func ExtractGoComments(pkgPath, dir string) (map[string]string, error) {
+	comments := make(map[string]string)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if p != dir && (name == "testdata" ||
+			strings.HasPrefix(name, ".") ||
+			strings.HasPrefix(name, "_")) {
+			return filepath.SkipDir
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		return extractDirComments(
+			path.Join(pkgPath, filepath.ToSlash(rel)),
+			p,
+			comments,
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// extractDirComments adds the comments of the Go files of a single
+// directory to the comments.
+func extractDirComments(pkgPath, dir string, comments map[string]string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() ||
+			!strings.HasSuffix(name, ".go") ||
+			strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(
+			fset,
+			filepath.Join(dir, name),
+			nil,
+			parser.ParseComments,
+		)
+		if err != nil {
+			return err
+		}
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				extractTypeComments(
+					pkgPath,
+					gen,
+					spec.(*ast.TypeSpec),
+					comments,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// extractTypeComments adds the comments of a type and of its fields to the
+// comments.
+func extractTypeComments(
+	pkgPath string,
+	gen *ast.GenDecl,
+	spec *ast.TypeSpec,
+	comments map[string]string,
+) {
+	key := pkgPath + "." + spec.Name.Name
+	doc := spec.Doc
+	if doc == nil && len(gen.Specs) == 1 {
+		doc = gen.Doc
+	}
+	if text := commentText(doc); text != "" {
+		comments[key] = text
+	}
+	st, ok := spec.Type.(*ast.StructType)
+	if !ok {
+		return
+	}
+	for _, field := range st.Fields.List {
+		text := commentText(field.Doc)
+		if text == "" {
+			text = commentText(field.Comment)
+		}
+		if text == "" {
+			continue
+		}
+		for _, name := range field.Names {
+			comments[key+"."+name.Name] = text
+		}
+	}
+}
+
+// commentText returns the text of a comment group with the lines of each
+// paragraph joined by spaces.
+func commentText(group *ast.CommentGroup) string {
+	if group == nil {
+		return ""
+	}
+	paragraphs := strings.Split(strings.TrimSpace(group.Text()), "\n\n")
+	for i, paragraph := range paragraphs {
+		paragraphs[i] = strings.Join(strings.Fields(paragraph), " ")
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
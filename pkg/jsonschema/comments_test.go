@@ -0,0 +1,42 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/conneroisu/groq-go/pkg/jsonschema/examples"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoCommentsFromSource(t *testing.T) {
+	r := &Reflector{}
+	require.NoError(t, r.AddGoComments(
+		"github.com/conneroisu/groq-go/pkg/jsonschema/examples",
+		"./examples",
+	))
+	compareSchemaOutput(t, "testdata/go_comments.json", r, &examples.User{})
+}
+
+func TestExtractGoComments(t *testing.T) {
+	comments, err := ExtractGoComments("example.com/root", ".")
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		"Pet defines the user's fury friend.",
+		comments["example.com/root/examples.Pet"],
+	)
+	assert.Equal(
+		t,
+		"This comment will be used",
+		comments["example.com/root/examples.Plant.Variant"],
+	)
+	assert.Equal(
+		t,
+		"Multicellular is true if the plant is multicellular",
+		comments["example.com/root/examples.Plant.Multicellular"],
+	)
+	assert.Contains(t, comments, "example.com/root.Reflector.CommentMap")
+	for key := range comments {
+		assert.NotContains(t, key, "testdata")
+	}
+}
@@ -0,0 +1,21 @@
+// Package jsonschema reflects Go types into JSON Schemas and validates JSON
+// values against them.
+//
+// It is the reflector the groq client uses to build the response formats of
+// structured chat completions and the parameters of tools, so schemas built
+// with a zero Reflector match exactly what the client sends.
+//
+// A Reflector is configured through its fields: Namer and KeyNamer set the
+// naming strategy, AllowAdditionalProperties the additionalProperties
+// default, DoNotReference and ExpandedStruct whether types are referenced
+// from definitions or inlined, FieldNameTag the tag read for field names
+// and CommentMap the Go doc comments used as descriptions.
+//
+// Types can customize their schema by implementing any of:
+//
+//	JSONSchema() *Schema
+//	JSONSchemaExtend(*Schema)
+//	JSONSchemaAlias() any
+//	JSONSchemaProperty(prop string) any
+//	GetFieldDocString(fieldName string) string
+package jsonschema
@@ -0,0 +1,37 @@
+// Package examples contains the types used to test reading schema
+// descriptions from Go comments.
+package examples
+
+type (
+	// User is used as a base to provide tests for comments.
+	User struct {
+		// Unique sequential identifier.
+		ID int `json:"id" jsonschema:"required"`
+		// This comment will be ignored
+		Name    string         `json:"name" jsonschema:"required,minLength=1,maxLength=20,pattern=.*,description=this is a property,title=the name,example=joe,example=lucy,default=alex"`
+		Friends []int          `json:"friends,omitempty" jsonschema_description:"list of IDs, omitted when empty"`
+		Tags    map[string]any `json:"tags,omitempty"`
+		// An array of pets the user cares for.
+		Pets Pets `json:"pets"`
+		// Set of animal names to pets
+		NamedPets NamedPets `json:"named_pets"`
+		// Set of plants that the user likes
+		Plants []*Plant `json:"plants" jsonschema:"title=Plants"`
+	}
+	// Pet defines the user's fury friend.
+	Pet struct {
+		// Name of the animal.
+		Name string `json:"name" jsonschema:"title=Name"`
+	}
+	// Pets is a collection of Pet objects.
+	Pets []*Pet
+	// NamedPets is a map of animal names to pets.
+	NamedPets map[string]*Pet
+	// Plant represents the plants the user might have and serves as a test
+	// of structs inside a `type` set.
+	Plant struct {
+		Variant string `json:"variant" jsonschema:"title=Variant"` // This comment will be used
+		// Multicellular is true if the plant is multicellular
+		Multicellular bool `json:"multicellular,omitempty" jsonschema:"title=Multicellular"` // This comment will be ignored
+	}
+)
@@ -0,0 +1,87 @@
+package jsonschema
+
+import (
+	"iter"
+
+	"github.com/conneroisu/groq-go/internal/omap"
+)
+
+// Properties are the properties of an object schema in the order they are
+// set, which is the order they are marshaled in.
+//
+// The zero value is an empty set of properties ready to use.
+type Properties struct {
+	m *omap.OrderedMap[string, *Schema]
+}
+
+// NewProperties is a helper method to instantiate a new, empty set of
+// properties.
+func NewProperties() *Properties {
+	return &Properties{m: omap.New[string, *Schema]()}
+}
+
+// Get returns the schema of the property with the name and whether it is
+// present.
+func (p *Properties) Get(name string) (*Schema, bool) {
+	if p == nil || p.m == nil {
+		return nil, false
+	}
+	return p.m.Get(name)
+}
+
+// Set sets the schema of the property with the name, keeping the position of
+// an existing property and adding a new one last.
+func (p *Properties) Set(name string, schema *Schema) {
+	if p.m == nil {
+		p.m = omap.New[string, *Schema]()
+	}
+	p.m.Set(name, schema)
+}
+
+// Delete removes the property with the name, returning its schema and
+// whether it was present.
+func (p *Properties) Delete(name string) (*Schema, bool) {
+	if p == nil || p.m == nil {
+		return nil, false
+	}
+	return p.m.Delete(name)
+}
+
+// Len returns the number of properties.
+func (p *Properties) Len() int {
+	if p == nil {
+		return 0
+	}
+	return p.m.Len()
+}
+
+// All returns an iterator over the names and schemas of the properties in
+// order.
+func (p *Properties) All() iter.Seq2[string, *Schema] {
+	return func(yield func(string, *Schema) bool) {
+		if p == nil {
+			return
+		}
+		for pair := p.m.Oldest(); pair != nil; pair = pair.Next() {
+			if !yield(pair.Key, pair.Value) {
+				return
+			}
+		}
+	}
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (p *Properties) MarshalJSON() ([]byte, error) {
+	if p.m == nil {
+		return []byte("{}"), nil
+	}
+	return p.m.MarshalJSON()
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (p *Properties) UnmarshalJSON(data []byte) error {
+	if p.m == nil {
+		p.m = omap.New[string, *Schema]()
+	}
+	return p.m.UnmarshalJSON(data)
+}
@@ -0,0 +1,44 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/conneroisu/groq-go/pkg/jsonschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestProperties tests that properties keep the order they are set in.
+func TestProperties(t *testing.T) {
+	a := assert.New(t)
+	var properties jsonschema.Properties
+	a.Zero(properties.Len())
+	properties.Set("b", &jsonschema.Schema{Type: "string"})
+	properties.Set("a", &jsonschema.Schema{Type: "integer"})
+	properties.Set("b", &jsonschema.Schema{Type: "boolean"})
+	a.Equal(2, properties.Len())
+	b, err := json.Marshal(&properties)
+	require.NoError(t, err)
+	a.JSONEq(`{"b":{"type":"boolean"},"a":{"type":"integer"}}`, string(b))
+
+	var names []string
+	for name := range properties.All() {
+		names = append(names, name)
+	}
+	a.Equal([]string{"b", "a"}, names)
+
+	removed, ok := properties.Delete("b")
+	a.True(ok)
+	a.Equal("boolean", removed.Type)
+	_, ok = properties.Get("b")
+	a.False(ok)
+
+	unmarshaled := jsonschema.NewProperties()
+	require.NoError(t, json.Unmarshal([]byte(`{"z":{"type":"null"},"y":{}}`), unmarshaled))
+	names = names[:0]
+	for name := range unmarshaled.All() {
+		names = append(names, name)
+	}
+	a.Equal([]string{"z", "y"}, names)
+}
@@ -1,11 +1,11 @@
-package schema
+package jsonschema
 
 import "reflect"
 
 type (
 
-	// A reflector reflects values into a Schema.
-	reflector struct {
+	// A Reflector reflects values into a Schema.
+	Reflector struct {
 		// BaseSchemaID defines the URI that will be used as a base to determine
 		// Schema IDs for models. For example, a base Schema ID of `
 		// https://conneroh.com/schemas` when defined with a struct called
@@ -15,7 +15,7 @@ type (
 		// If no `BaseSchemaID` is provided, we'll take the type's complete
 		// package path and use that as a base instead. Set `Anonymous` to try
 		// if you do not want to include a schema ID.
-		BaseSchemaID schemaID
+		BaseSchemaID ID
 		// Anonymous when true will hide the auto-generated Schema ID and
 		// provide what is known as an "anonymous schema". As a rule, this is
 		// not recommended.
@@ -59,7 +59,7 @@ type (
 		// to be referenced by their ID instead of being embedded into the
 		// current schema definitions. Reflected types will never be pointers,
 		// only underlying elements.
-		Lookup func(reflect.Type) schemaID
+		Lookup func(reflect.Type) ID
 		// Mapper is a function that can be used to map custom Go types to
 		// jsonschema schemas.
 		Mapper func(reflect.Type) *Schema
@@ -88,24 +88,24 @@ type (
 		//
 		//   map[string]string{"github.com/conneroisu/groq.Reflector.DoNotReference": "Do not reference definitions."}
 		//
-		// See also: AddGoComments
+		// See also: AddGoComments and cmd/generate-jsonschema-comments
 		CommentMap map[string]string
 	}
 )
 
 // Reflect reflects to Schema from a value.
-func (r *reflector) Reflect(v any) *Schema {
+func (r *Reflector) Reflect(v any) *Schema {
 	return r.ReflectFromType(reflect.TypeOf(v))
 }
 
 // ReflectFromType generates root schema
-func (r *reflector) ReflectFromType(t reflect.Type) *Schema {
+func (r *Reflector) ReflectFromType(t reflect.Type) *Schema {
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem() // re-assign from pointer
 	}
 	name := r.typeName(t)
 	s := new(Schema)
-	definitions := schemaDefinitions{}
+	definitions := Definitions{}
 	s.Definitions = definitions
 	bs := r.reflectTypeToSchemaWithID(definitions, t)
 	if r.ExpandedStruct {
@@ -118,7 +118,7 @@ func (r *reflector) ReflectFromType(t reflect.Type) *Schema {
 	if !r.Anonymous && s.ID == EmptyID {
 		baseSchemaID := r.BaseSchemaID
 		if baseSchemaID == EmptyID {
-			i := schemaID("https://" + t.PkgPath())
+			i := ID("https://" + t.PkgPath())
 			if err := i.Validate(); err == nil {
 				// it's okay to silently ignore URL errors
 				baseSchemaID = i
@@ -137,11 +137,11 @@ func (r *reflector) ReflectFromType(t reflect.Type) *Schema {
 
 // SetBaseSchemaID is a helper use to be able to set the reflectors base
 // schema ID from a string as opposed to then ID instance.
-func (r *reflector) SetBaseSchemaID(identifier string) {
-	r.BaseSchemaID = schemaID(identifier)
+func (r *Reflector) SetBaseSchemaID(identifier string) {
+	r.BaseSchemaID = ID(identifier)
 }
-func (r *reflector) refOrReflectTypeToSchema(
-	definitions schemaDefinitions,
+func (r *Reflector) refOrReflectTypeToSchema(
+	definitions Definitions,
 	t reflect.Type,
 ) *Schema {
 	id := r.lookupID(t)
@@ -156,8 +156,8 @@ func (r *reflector) refOrReflectTypeToSchema(
 	}
 	return r.reflectTypeToSchemaWithID(definitions, t)
 }
-func (r *reflector) reflectTypeToSchemaWithID(
-	defs schemaDefinitions,
+func (r *Reflector) reflectTypeToSchemaWithID(
+	defs Definitions,
 	t reflect.Type,
 ) *Schema {
 	s := r.reflectTypeToSchema(defs, t)
@@ -171,8 +171,8 @@ func (r *reflector) reflectTypeToSchemaWithID(
 	}
 	return s
 }
-func (r *reflector) reflectTypeToSchema(
-	definitions schemaDefinitions,
+func (r *Reflector) reflectTypeToSchema(
+	definitions Definitions,
 	t reflect.Type,
 ) *Schema {
 	// only try to reflect non-pointers
@@ -253,8 +253,8 @@ func (r *reflector) reflectTypeToSchema(
 	}
 	return st
 }
-func (r *reflector) reflectCustomSchema(
-	definitions schemaDefinitions,
+func (r *Reflector) reflectCustomSchema(
+	definitions Definitions,
 	t reflect.Type,
 ) *Schema {
 	if t.Kind() == reflect.Ptr {
@@ -271,8 +271,8 @@ func (r *reflector) reflectCustomSchema(
 	}
 	return nil
 }
-func (r *reflector) reflectSchemaExtend(
-	definitions schemaDefinitions,
+func (r *Reflector) reflectSchemaExtend(
+	definitions Definitions,
 	t reflect.Type,
 	s *Schema,
 ) *Schema {
@@ -285,8 +285,8 @@ func (r *reflector) reflectSchemaExtend(
 	}
 	return s
 }
-func (r *reflector) reflectSliceOrArray(
-	definitions schemaDefinitions,
+func (r *Reflector) reflectSliceOrArray(
+	definitions Definitions,
 	t reflect.Type,
 	st *Schema,
 ) {
@@ -310,8 +310,8 @@ func (r *reflector) reflectSliceOrArray(
 	st.Type = "array"
 	st.Items = r.refOrReflectTypeToSchema(definitions, t.Elem())
 }
-func (r *reflector) reflectMap(
-	definitions schemaDefinitions,
+func (r *Reflector) reflectMap(
+	definitions Definitions,
 	t reflect.Type,
 	st *Schema,
 ) {
@@ -341,8 +341,8 @@ func (r *reflector) reflectMap(
 }
 
 // Reflects a struct to a JSON Schema type.
-func (r *reflector) reflectStruct(
-	definitions schemaDefinitions,
+func (r *Reflector) reflectStruct(
+	definitions Definitions,
 	t reflect.Type,
 	s *Schema,
 ) {
@@ -359,7 +359,7 @@ func (r *reflector) reflectStruct(
 	}
 	r.addDefinition(definitions, t, s)
 	s.Type = "object"
-	s.Properties = newProperties()
+	s.Properties = NewProperties()
 	s.Description = r.lookupComment(t, "")
 	if r.AssignAnchor {
 		s.Anchor = t.Name()
@@ -379,9 +379,9 @@ func (r *reflector) reflectStruct(
 	}
 }
 
-func (r *reflector) reflectStructFields(
+func (r *Reflector) reflectStructFields(
 	st *Schema,
-	definitions schemaDefinitions,
+	definitions Definitions,
 	t reflect.Type,
 ) {
 	if t.Kind() == reflect.Ptr {
@@ -462,7 +462,7 @@ func (r *reflector) reflectStructFields(
 	}
 }
 
-func (r *reflector) lookupComment(t reflect.Type, name string) string {
+func (r *Reflector) lookupComment(t reflect.Type, name string) string {
 	if r.CommentMap == nil {
 		return ""
 	}
@@ -475,8 +475,8 @@ func (r *reflector) lookupComment(t reflect.Type, name string) string {
 
 // addDefinition will append the provided schema. If needed, an ID and anchor
 // will also be added.
-func (r *reflector) addDefinition(
-	definitions schemaDefinitions,
+func (r *Reflector) addDefinition(
+	definitions Definitions,
 	t reflect.Type,
 	s *Schema,
 ) {
@@ -489,8 +489,8 @@ func (r *reflector) addDefinition(
 
 // refDefinition will provide a schema with a reference to an existing
 // definition.
-func (r *reflector) refDefinition(
-	definitions schemaDefinitions,
+func (r *Reflector) refDefinition(
+	definitions Definitions,
 	t reflect.Type,
 ) *Schema {
 	if r.DoNotReference {
@@ -507,7 +507,7 @@ func (r *reflector) refDefinition(
 		Ref: "#/$defs/" + name,
 	}
 }
-func (r *reflector) lookupID(t reflect.Type) schemaID {
+func (r *Reflector) lookupID(t reflect.Type) ID {
 	if r.Lookup != nil {
 		if t.Kind() == reflect.Ptr {
 			t = t.Elem()
@@ -0,0 +1,71 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/conneroisu/groq-go/pkg/jsonschema"
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	color string
+	shape struct {
+		Color color `json:"color"`
+		Sides int   `json:"sides"`
+	}
+)
+
+// JSONSchema implements a custom schema from outside the package.
+func (color) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: []any{"red", "green"},
+	}
+}
+
+// TestReflectorPublic tests configuring a Reflector and custom schemas from
+// outside the package.
+func TestReflectorPublic(t *testing.T) {
+	a := assert.New(t)
+	r := &jsonschema.Reflector{
+		Anonymous:      true,
+		DoNotReference: true,
+		KeyNamer:       strings.ToUpper,
+	}
+	s := r.Reflect(&shape{})
+	b, err := json.Marshal(s)
+	a.NoError(err)
+	a.JSONEq(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"COLOR": {"type": "string", "enum": ["red", "green"]},
+			"SIDES": {"type": "integer"}
+		},
+		"additionalProperties": false,
+		"required": ["COLOR", "SIDES"]
+	}`, string(b))
+	a.NoError(s.Validate([]byte(`{"COLOR":"red","SIDES":3}`)))
+	a.Error(s.Validate([]byte(`{"COLOR":"blue","SIDES":3}`)))
+
+	properties := jsonschema.NewProperties()
+	properties.Set("name", &jsonschema.Schema{Type: "string"})
+	custom := &jsonschema.Schema{Type: "object", Properties: properties}
+	a.NoError(custom.Validate([]byte(`{"name":"a"}`)))
+	a.Error(custom.Validate([]byte(`{"name":1}`)))
+}
+
+// TestReflectSchemaMatchesZeroReflector tests that ReflectSchema produces
+// the output of a zero Reflector.
+func TestReflectSchemaMatchesZeroReflector(t *testing.T) {
+	a := assert.New(t)
+	s, err := jsonschema.ReflectSchema(&shape{})
+	a.NoError(err)
+	a.Equal(new(jsonschema.Reflector).Reflect(&shape{}), s)
+	a.Equal(
+		jsonschema.ID("https://github.com/conneroisu/groq-go/pkg/jsonschema_test/shape"),
+		s.ID,
+	)
+}
@@ -1,4 +1,4 @@
-package schema
+package jsonschema
 
 import (
 	"bytes"
@@ -11,24 +11,47 @@ import (
 	"strconv"
 	"strings"
 	"time"
-
-	"github.com/conneroisu/groq-go/internal/omap"
 )
 
 const (
 	// version is the JSON Schema version.
 	version = "https://json-schema.org/draft/2020-12/schema"
 	// EmptyID is used to explicitly define an ID with no value.
-	EmptyID schemaID = ""
+	EmptyID ID = ""
 )
 
 // ReflectSchema returns a schema from a value.
+//
+// It uses a zero Reflector, which is what the groq client uses to build the
+// json schema response formats of ChatCompletionJSON.
 func ReflectSchema(a any) (*Schema, error) {
-	r := &reflector{}
+	r := &Reflector{}
 	schema := r.ReflectFromType(reflect.TypeOf(a))
 	return schema, nil
 }
 
+// ReflectExpandedSchema returns an anonymous schema from a value with the
+// value's own type expanded in the root instead of referenced.
+//
+// Nested named types are still referenced from the root's definitions.
+func ReflectExpandedSchema(a any) (*Schema, error) {
+	t := reflect.TypeOf(a)
+	if t == nil {
+		return nil, fmt.Errorf("cannot reflect the schema of nil")
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot expand the schema of non-struct type %s", t)
+	}
+	// anonymous structs have no definition and are always expanded
+	r := &Reflector{Anonymous: true, ExpandedStruct: t.Name() != ""}
+	schema := r.ReflectFromType(t)
+	schema.Version = ""
+	return schema, nil
+}
+
 // Available Go defined types for JSON Schema Validation.
 //
 // https://datatracker.ietf.org/doc/html/draft-wright-json-schema-validation-00#section-7.3
@@ -73,7 +96,7 @@ type (
 		//
 		// The value of this field MUST be a string.  This string SHOULD be a
 		// URI.
-		ID schemaID `json:"$id,omitempty"`
+		ID ID `json:"$id,omitempty"`
 		// Anchor is the anchor of the schema as specified in section 8.2.2 of RFC
 		// draft-bhutton-json-schema-00.
 		//
@@ -120,7 +143,7 @@ type (
 		//
 		// Omitting this field has the same assertion behavior as an empty
 		// object.
-		Definitions schemaDefinitions `json:"$defs,omitempty"`
+		Definitions Definitions `json:"$defs,omitempty"`
 		// Comments specifies a comment for the schema as
 		// specified RFC draft-bhutton-json-schema-00 section 8.3
 		//
@@ -328,7 +351,7 @@ type (
 		//
 		// Omitting this field has the same assertion behavior as an empty
 		// object.
-		Properties *omap.OrderedMap[string, *Schema] `json:"properties,omitempty"`
+		Properties *Properties `json:"properties,omitempty"`
 		// PatternProperties are the pattern properties of the schema as specified in section 10.3.2.2 of RFC
 		// draft-bhutton-json-schema-00.
 		//
@@ -847,15 +870,15 @@ type (
 		// Special boolean representation of the Schema - section 4.3.2
 		boolean *bool
 	}
-	// schemaDefinitions hold schema schemaDefinitions.
+	// Definitions hold the definitions of a schema keyed by name.
 	//
 	// http://json-schema.org/latest/json-schema-validation.html#rfc.section.5.26
 	//
 	// RFC draft-wright-json-schema-validation-00, section 5.26
-	schemaDefinitions map[string]*Schema
-	// schemaID represents a Schema schemaID type which should always be a URI.
+	Definitions map[string]*Schema
+	// ID represents a Schema ID type which should always be a URI.
 	// See draft-bhutton-json-schema-00 section 8.2.1
-	schemaID string
+	ID string
 	// customSchemaImpl is used to detect if the type provides it's own
 	// custom Schema Type definition to use instead. Very useful for situations
 	// where there are custom JSON Marshal and Unmarshal methods.
@@ -1274,13 +1297,13 @@ func parseUint(num string) *uint64 {
 	}
 	return &val
 }
-func (r *reflector) fieldNameTag() string {
+func (r *Reflector) fieldNameTag() string {
 	if r.FieldNameTag != "" {
 		return r.FieldNameTag
 	}
 	return "json"
 }
-func (r *reflector) reflectFieldName(
+func (r *Reflector) reflectFieldName(
 	f reflect.StructField,
 ) (string, bool, bool, bool) {
 	jsonTagString := f.Tag.Get(r.fieldNameTag())
@@ -1378,7 +1401,7 @@ func (t *Schema) MarshalJSON() ([]byte, error) {
 	b[len(b)-1] = ','
 	return append(b, m[1:]...), nil
 }
-func (r *reflector) typeName(t reflect.Type) string {
+func (r *Reflector) typeName(t reflect.Type) string {
 	if r.Namer != nil {
 		if name := r.Namer(t); name != "" {
 			return name
@@ -1423,16 +1446,10 @@ func ToSnakeCase(str string) string {
 	return strings.ToLower(snake)
 }
 
-// newProperties is a helper method to instantiate a new properties ordered
-// map.
-func newProperties() *omap.OrderedMap[string, *Schema] {
-	return omap.New[string, *Schema]()
-}
-
 // Validate is used to check if the ID looks like a proper schema.
 // This is done by parsing the ID as a URL and checking it has all the
 // relevant parts.
-func (i schemaID) Validate() error {
+func (i ID) Validate() error {
 	u, err := url.Parse(string(i))
 	if err != nil {
 		return fmt.Errorf("invalid URL: %w", err)
@@ -1453,34 +1470,34 @@ func (i schemaID) Validate() error {
 }
 
 // Anchor sets the anchor part of the schema URI.
-func (i schemaID) Anchor(name string) schemaID {
+func (i ID) Anchor(name string) ID {
 	b := i.Base()
-	return schemaID(string(b) + "#" + name)
+	return ID(string(b) + "#" + name)
 }
 
 // Def adds or replaces a definition identifier.
-func (i schemaID) Def(name string) schemaID {
+func (i ID) Def(name string) ID {
 	b := i.Base()
-	return schemaID(string(b) + "#/$defs/" + name)
+	return ID(string(b) + "#/$defs/" + name)
 }
 
 // Add appends the provided path to the id, and removes any
 // anchor data that might be there.
-func (i schemaID) Add(path string) schemaID {
+func (i ID) Add(path string) ID {
 	b := i.Base()
 	if !strings.HasPrefix(path, "/") {
 		path = "/" + path
 	}
-	return schemaID(string(b) + path)
+	return ID(string(b) + path)
 }
 
 // Base removes any anchor information from the schema
-func (i schemaID) Base() schemaID {
+func (i ID) Base() ID {
 	s := string(i)
 	li := strings.LastIndex(s, "#")
 	if li != -1 {
 		s = s[0:li]
 	}
 	s = strings.TrimRight(s, "/")
-	return schemaID(s)
+	return ID(s)
 }
@@ -1,4 +1,4 @@
-package schema
+package jsonschema
 
 import (
 	"encoding/json"
@@ -19,7 +19,7 @@ import (
 
 func TestID(t *testing.T) {
 	base := "https://github.com/conneroisu/groq-go/schema"
-	id := schemaID(base)
+	id := ID(base)
 
 	assert.Equal(t, base, string(id))
 
@@ -37,7 +37,7 @@ func TestID(t *testing.T) {
 }
 
 func TestIDValidation(t *testing.T) {
-	id := schemaID("https://invopop.com/schema/user")
+	id := ID("https://invopop.com/schema/user")
 	assert.NoError(t, id.Validate())
 
 	id = "https://encoding/json"
@@ -365,7 +365,7 @@ func (CustomSliceType) JSONSchema() *Schema {
 type CustomMapType map[string]string
 
 func (CustomMapType) JSONSchema() *Schema {
-	properties := newProperties()
+	properties := NewProperties()
 	properties.Set("key", &Schema{
 		Type: "string",
 	})
@@ -437,21 +437,21 @@ type PatternEqualsTest struct {
 }
 
 func TestReflector(t *testing.T) {
-	r := new(reflector)
+	r := new(Reflector)
 	s := "http://example.com/schema"
 	r.SetBaseSchemaID(s)
 	assert.EqualValues(t, s, r.BaseSchemaID)
 }
 
 func TestReflectFromType(t *testing.T) {
-	r := new(reflector)
+	r := new(Reflector)
 	tu := new(TestUser)
 	typ := reflect.TypeOf(tu)
 
 	s := r.ReflectFromType(typ)
 	assert.EqualValues(
 		t,
-		"https://github.com/conneroisu/groq-go/internal/schema/test-user",
+		"https://github.com/conneroisu/groq-go/pkg/jsonschema/test-user",
 		s.ID,
 	)
 
@@ -468,65 +468,65 @@ func TestReflectFromType(t *testing.T) {
 func TestSchemaGeneration(t *testing.T) {
 	tests := []struct {
 		typ       any
-		reflector *reflector
+		Reflector *Reflector
 		fixture   string
 	}{
 		{
 			&TestUser{},
-			&reflector{},
+			&Reflector{},
 			"testdata/test_user.json",
 		},
 		{
 			&UserWithAnchor{},
-			&reflector{},
+			&Reflector{},
 			"testdata/user_with_anchor.json",
 		},
 		{
 			&TestUser{},
-			&reflector{AssignAnchor: true},
+			&Reflector{AssignAnchor: true},
 			"testdata/test_user_assign_anchor.json",
 		},
 		{
 			&TestUser{},
-			&reflector{AllowAdditionalProperties: true},
+			&Reflector{AllowAdditionalProperties: true},
 			"testdata/allow_additional_props.json",
 		},
 		{
 			&TestUser{},
-			&reflector{RequiredFromJSONSchemaTags: true},
+			&Reflector{RequiredFromJSONSchemaTags: true},
 			"testdata/required_from_jsontags.json",
 		},
 		{
 			&TestUser{},
-			&reflector{ExpandedStruct: true},
+			&Reflector{ExpandedStruct: true},
 			"testdata/defaults_expanded_toplevel.json",
 		},
 		{
 			&TestUser{},
-			&reflector{IgnoredTypes: []any{GrandfatherType{}}},
+			&Reflector{IgnoredTypes: []any{GrandfatherType{}}},
 			"testdata/ignore_type.json",
 		},
 		{
 			&TestUser{},
-			&reflector{DoNotReference: true},
+			&Reflector{DoNotReference: true},
 			"testdata/no_reference.json",
 		},
 		{
 			&TestUser{},
-			&reflector{DoNotReference: true, AssignAnchor: true},
+			&Reflector{DoNotReference: true, AssignAnchor: true},
 			"testdata/no_reference_anchor.json",
 		},
 		{
 			&RootOneOf{},
-			&reflector{RequiredFromJSONSchemaTags: true},
+			&Reflector{RequiredFromJSONSchemaTags: true},
 			"testdata/oneof.json",
 		},
 		{
 			&RootAnyOf{},
-			&reflector{RequiredFromJSONSchemaTags: true},
+			&Reflector{RequiredFromJSONSchemaTags: true},
 			"testdata/anyof.json",
 		},
-		{&CustomTypeField{}, &reflector{
+		{&CustomTypeField{}, &Reflector{
 			Mapper: func(i reflect.Type) *Schema {
 				if i == reflect.TypeOf(CustomTime{}) {
 					return &Schema{
@@ -539,62 +539,62 @@ func TestSchemaGeneration(t *testing.T) {
 		}, "testdata/custom_type.json"},
 		{
 			LookupUser{},
-			&reflector{BaseSchemaID: "https://example.com/schemas"},
+			&Reflector{BaseSchemaID: "https://example.com/schemas"},
 			"testdata/base_schema_id.json",
 		},
-		{LookupUser{}, &reflector{
-			Lookup: func(i reflect.Type) schemaID {
+		{LookupUser{}, &Reflector{
+			Lookup: func(i reflect.Type) ID {
 				switch i {
 				case reflect.TypeOf(LookupUser{}):
-					return schemaID("https://example.com/schemas/lookup-user")
+					return ID("https://example.com/schemas/lookup-user")
 				case reflect.TypeOf(LookupName{}):
-					return schemaID("https://example.com/schemas/lookup-name")
+					return ID("https://example.com/schemas/lookup-name")
 				}
 				return EmptyID
 			},
 		}, "testdata/lookup.json"},
-		{&LookupUser{}, &reflector{
+		{&LookupUser{}, &Reflector{
 			BaseSchemaID:   "https://example.com/schemas",
 			ExpandedStruct: true,
 			AssignAnchor:   true,
-			Lookup: func(i reflect.Type) schemaID {
+			Lookup: func(i reflect.Type) ID {
 				switch i {
 				case reflect.TypeOf(LookupUser{}):
-					return schemaID("https://example.com/schemas/lookup-user")
+					return ID("https://example.com/schemas/lookup-user")
 				case reflect.TypeOf(LookupName{}):
-					return schemaID("https://example.com/schemas/lookup-name")
+					return ID("https://example.com/schemas/lookup-name")
 				}
 				return EmptyID
 			},
 		}, "testdata/lookup_expanded.json"},
 		{
 			&Outer{},
-			&reflector{ExpandedStruct: true},
+			&Reflector{ExpandedStruct: true},
 			"testdata/inlining_inheritance.json",
 		},
 		{
 			&OuterNamed{},
-			&reflector{ExpandedStruct: true},
+			&Reflector{ExpandedStruct: true},
 			"testdata/inlining_embedded.json",
 		},
 		{
 			&OuterNamed{},
-			&reflector{ExpandedStruct: true, AssignAnchor: true},
+			&Reflector{ExpandedStruct: true, AssignAnchor: true},
 			"testdata/inlining_embedded_anchored.json",
 		},
 		{
 			&OuterInlined{},
-			&reflector{ExpandedStruct: true},
+			&Reflector{ExpandedStruct: true},
 			"testdata/inlining_tag.json",
 		},
 		{
 			&OuterPtr{},
-			&reflector{ExpandedStruct: true},
+			&Reflector{ExpandedStruct: true},
 			"testdata/inlining_ptr.json",
 		},
-		{&MinValue{}, &reflector{}, "testdata/schema_with_minimum.json"},
-		{&TestNullable{}, &reflector{}, "testdata/nullable.json"},
-		{&GrandfatherType{}, &reflector{
+		{&MinValue{}, &Reflector{}, "testdata/schema_with_minimum.json"},
+		{&TestNullable{}, &Reflector{}, "testdata/nullable.json"},
+		{&GrandfatherType{}, &Reflector{
 			AdditionalFields: func(_ reflect.Type) []reflect.StructField {
 				return []reflect.StructField{
 					{
@@ -608,19 +608,19 @@ func TestSchemaGeneration(t *testing.T) {
 		}, "testdata/custom_additional.json"},
 		{
 			&TestDescriptionOverride{},
-			&reflector{},
+			&Reflector{},
 			"testdata/test_description_override.json",
 		},
-		{&CompactDate{}, &reflector{}, "testdata/compact_date.json"},
-		{&CustomSliceOuter{}, &reflector{}, "testdata/custom_slice_type.json"},
-		{&CustomMapOuter{}, &reflector{}, "testdata/custom_map_type.json"},
+		{&CompactDate{}, &Reflector{}, "testdata/compact_date.json"},
+		{&CustomSliceOuter{}, &Reflector{}, "testdata/custom_slice_type.json"},
+		{&CustomMapOuter{}, &Reflector{}, "testdata/custom_map_type.json"},
 		{
 			&CustomTypeFieldWithInterface{},
-			&reflector{},
+			&Reflector{},
 			"testdata/custom_type_with_interface.json",
 		},
-		{&RecursiveExample{}, &reflector{}, "testdata/recursive.json"},
-		{&KeyNamed{}, &reflector{
+		{&RecursiveExample{}, &Reflector{}, "testdata/recursive.json"},
+		{&KeyNamed{}, &Reflector{
 			KeyNamer: func(s string) string {
 				switch s {
 				case "ThisWasLeftAsIs":
@@ -643,29 +643,29 @@ func TestSchemaGeneration(t *testing.T) {
 				return "unknown case"
 			},
 		}, "testdata/keynamed.json"},
-		{MapType{}, &reflector{}, "testdata/map_type.json"},
-		{ArrayType{}, &reflector{}, "testdata/array_type.json"},
-		{SchemaExtendTest{}, &reflector{}, "testdata/custom_type_extend.json"},
-		{Expression{}, &reflector{}, "testdata/schema_with_expression.json"},
-		{&PatternTest{}, &reflector{}, "testdata/commas_in_pattern.json"},
+		{MapType{}, &Reflector{}, "testdata/map_type.json"},
+		{ArrayType{}, &Reflector{}, "testdata/array_type.json"},
+		{SchemaExtendTest{}, &Reflector{}, "testdata/custom_type_extend.json"},
+		{Expression{}, &Reflector{}, "testdata/schema_with_expression.json"},
+		{&PatternTest{}, &Reflector{}, "testdata/commas_in_pattern.json"},
 	}
 
 	for _, tt := range tests {
 		name := strings.TrimSuffix(filepath.Base(tt.fixture), ".json")
 		t.Run(name, func(t *testing.T) {
 			compareSchemaOutput(t,
-				tt.fixture, tt.reflector, tt.typ,
+				tt.fixture, tt.Reflector, tt.typ,
 			)
 		})
 	}
 }
 
 func TestBaselineUnmarshal(t *testing.T) {
-	r := &reflector{}
+	r := &Reflector{}
 	compareSchemaOutput(t, "testdata/test_user.json", r, &TestUser{})
 }
 
-func compareSchemaOutput(t *testing.T, f string, r *reflector, obj any) {
+func compareSchemaOutput(t *testing.T, f string, r *Reflector, obj any) {
 	t.Helper()
 	expectedJSON, err := os.ReadFile(f)
 	require.NoError(t, err)
@@ -725,7 +725,7 @@ func TestArrayExtraTags(t *testing.T) {
 		TestURIs []string `jsonschema:"type=array,format=uri,pattern=^https://.*"`
 	}
 
-	r := new(reflector)
+	r := new(Reflector)
 	schema := r.Reflect(&URIArray{})
 	d := schema.Definitions["URIArray"]
 	require.NotNil(t, d)
@@ -746,7 +746,7 @@ func TestFieldNameTag(t *testing.T) {
 		Count int    `yaml:"count"`
 	}
 
-	r := reflector{
+	r := Reflector{
 		FieldNameTag: "yaml",
 	}
 	compareSchemaOutput(t, "testdata/test_config.json", &r, &Config{})
@@ -760,7 +760,7 @@ func TestFieldOneOfRef(t *testing.T) {
 		IPAddressesAny []any `json:"ip_addresses_any,omitempty" jsonschema:"anyof_ref=#/$defs/ipv4;#/$defs/ipv6"`
 	}
 
-	r := &reflector{}
+	r := &Reflector{}
 	compareSchemaOutput(t, "testdata/oneof_ref.json", r, &Server{})
 }
 
@@ -770,7 +770,7 @@ func TestNumberHandling(t *testing.T) {
 		Float32 float32 `json:"float32" jsonschema:"default=12.5"`
 	}
 
-	r := &reflector{}
+	r := &Reflector{}
 	compareSchemaOutput(
 		t,
 		"testdata/number_handling.json",
@@ -787,7 +787,7 @@ func TestArrayHandling(t *testing.T) {
 		MinVal []float64 `json:"min_val" jsonschema:"minimum=2.5"`
 	}
 
-	r := &reflector{}
+	r := &Reflector{}
 	compareSchemaOutput(t, "testdata/array_handling.json", r, &ArrayHandler{})
 	fixtureContains(t, "testdata/array_handling.json", `"minLength": 2`)
 	fixtureContains(t, "testdata/array_handling.json", `"minimum": 2.5`)
@@ -801,7 +801,7 @@ func TestUnsignedIntHandling(t *testing.T) {
 		MaxItems []string `json:"max_items" jsonschema:"maxItems=0"`
 	}
 
-	r := &reflector{}
+	r := &Reflector{}
 	compareSchemaOutput(
 		t,
 		"testdata/unsigned_int_handling.json",
@@ -820,7 +820,7 @@ func TestJSONSchemaFormat(t *testing.T) {
 		Odds  []string `json:"odds"  jsonschema:"format=odd"`
 	}
 
-	r := &reflector{}
+	r := &Reflector{}
 	compareSchemaOutput(
 		t,
 		"testdata/with_custom_format.json",
@@ -856,7 +856,7 @@ func (AliasObjectB) JSONSchemaAlias() any {
 }
 
 func TestJSONSchemaProperty(t *testing.T) {
-	r := &reflector{}
+	r := &Reflector{}
 	compareSchemaOutput(
 		t,
 		"testdata/schema_property_alias.json",
@@ -866,7 +866,7 @@ func TestJSONSchemaProperty(t *testing.T) {
 }
 
 func TestJSONSchemaAlias(t *testing.T) {
-	r := &reflector{}
+	r := &Reflector{}
 	compareSchemaOutput(t, "testdata/schema_alias.json", r, &AliasObjectB{})
 	compareSchemaOutput(t, "testdata/schema_alias_2.json", r, &AliasObjectC{})
 }
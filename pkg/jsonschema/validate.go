@@ -0,0 +1,495 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+)
+
+// patterns caches the compiled regular expressions of schema patterns.
+var patterns sync.Map
+
+type (
+	// validator validates JSON values against a root schema.
+	validator struct {
+		root       *Schema
+		violations []groqerr.SchemaViolation
+	}
+)
+
+// Validate validates the JSON document against the schema.
+//
+// It returns a groqerr.ErrSchemaValidation listing every violation if the
+// document does not match the schema.
+func (t *Schema) Validate(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return groqerr.ErrSchemaValidation{
+			Violations: []groqerr.SchemaViolation{{
+				Path:    "$",
+				Message: fmt.Sprintf("invalid JSON: %v", err),
+			}},
+		}
+	}
+	return t.ValidateValue(value)
+}
+
+// ValidateValue validates a decoded JSON value against the schema.
+//
+// Numbers may be json.Number or any Go numeric type.
+func (t *Schema) ValidateValue(value any) error {
+	v := &validator{root: t}
+	v.validate(t, value, "$")
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return groqerr.ErrSchemaValidation{Violations: v.violations}
+}
+
+// fail records a violation at the path.
+func (v *validator) fail(path, format string, args ...any) {
+	v.violations = append(v.violations, groqerr.SchemaViolation{
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// valid reports whether the value matches the schema without recording
+// violations.
+func (v *validator) valid(s *Schema, value any) bool {
+	sub := &validator{root: v.root}
+	sub.validate(s, value, "$")
+	return len(sub.violations) == 0
+}
+
+// resolve returns the definition a local "#/$defs/<name>" ref points to.
+func (v *validator) resolve(ref string) (*Schema, bool) {
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return nil, false
+	}
+	s, ok := v.root.Definitions[name]
+	return s, ok && s != nil
+}
+
+// validate validates the value at the path against the schema.
+func (v *validator) validate(s *Schema, value any, path string) {
+	if s == nil {
+		return
+	}
+	if s.boolean != nil {
+		if !*s.boolean {
+			v.fail(path, "no value is allowed")
+		}
+		return
+	}
+	if s.Ref != "" {
+		def, ok := v.resolve(s.Ref)
+		if !ok {
+			v.fail(path, "unresolvable reference %s", s.Ref)
+			return
+		}
+		v.validate(def, value, path)
+	}
+	if s.Type != "" && !hasType(value, s.Type) {
+		v.fail(path, "expected %s, got %s", s.Type, typeOf(value))
+		return
+	}
+	if len(s.Enum) > 0 && !containsValue(s.Enum, value) {
+		v.fail(path, "must be one of %s", formatValues(s.Enum))
+	}
+	if s.Const != nil && !equalValues(s.Const, value) {
+		v.fail(path, "must be %s", formatValues([]any{s.Const}))
+	}
+	v.validateCombinators(s, value, path)
+	switch value := value.(type) {
+	case map[string]any:
+		v.validateObject(s, value, path)
+	case []any:
+		v.validateArray(s, value, path)
+	case string:
+		v.validateString(s, value, path)
+	case bool, nil:
+	default:
+		if n, ok := toFloat(value); ok {
+			v.validateNumber(s, n, path)
+		}
+	}
+}
+
+// validateCombinators validates the allOf, anyOf, oneOf and not keywords.
+func (v *validator) validateCombinators(s *Schema, value any, path string) {
+	for _, sub := range s.AllOf {
+		v.validate(sub, value, path)
+	}
+	if len(s.AnyOf) > 0 {
+		matched := false
+		for _, sub := range s.AnyOf {
+			if v.valid(sub, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.fail(path, "must match at least one of the anyOf schemas")
+		}
+	}
+	if len(s.OneOf) > 0 {
+		matched := 0
+		for _, sub := range s.OneOf {
+			if v.valid(sub, value) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			v.fail(path, "must match exactly one of the oneOf schemas, matched %d", matched)
+		}
+	}
+	if s.Not != nil && v.valid(s.Not, value) {
+		v.fail(path, "must not match the not schema")
+	}
+}
+
+// validateObject validates the keywords applying to objects.
+func (v *validator) validateObject(s *Schema, object map[string]any, path string) {
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			v.fail(propertyPath(path, name), "is required")
+		}
+	}
+	if s.MinProperties != nil && uint64(len(object)) < *s.MinProperties {
+		v.fail(path, "must have at least %d properties", *s.MinProperties)
+	}
+	if s.MaxProperties != nil && uint64(len(object)) > *s.MaxProperties {
+		v.fail(path, "must have at most %d properties", *s.MaxProperties)
+	}
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		matched := false
+		if s.Properties != nil {
+			if prop, ok := s.Properties.Get(key); ok {
+				matched = true
+				v.validate(prop, object[key], propertyPath(path, key))
+			}
+		}
+		for pattern, prop := range s.PatternProperties {
+			re, err := compilePattern(pattern)
+			if err == nil && re.MatchString(key) {
+				matched = true
+				v.validate(prop, object[key], propertyPath(path, key))
+			}
+		}
+		if matched || s.AdditionalProperties == nil {
+			continue
+		}
+		if s.AdditionalProperties.boolean != nil &&
+			!*s.AdditionalProperties.boolean {
+			v.fail(propertyPath(path, key), "additional property is not allowed")
+			continue
+		}
+		v.validate(s.AdditionalProperties, object[key], propertyPath(path, key))
+	}
+}
+
+// validateArray validates the keywords applying to arrays.
+func (v *validator) validateArray(s *Schema, array []any, path string) {
+	if s.MinItems != nil && uint64(len(array)) < *s.MinItems {
+		v.fail(path, "must have at least %d items", *s.MinItems)
+	}
+	if s.MaxItems != nil && uint64(len(array)) > *s.MaxItems {
+		v.fail(path, "must have at most %d items", *s.MaxItems)
+	}
+	if s.UniqueItems {
+		for i := range array {
+			for j := range i {
+				if equalValues(array[i], array[j]) {
+					v.fail(itemPath(path, i), "duplicates item %d", j)
+				}
+			}
+		}
+	}
+	for i, item := range array {
+		if i < len(s.PrefixItems) {
+			v.validate(s.PrefixItems[i], item, itemPath(path, i))
+			continue
+		}
+		v.validate(s.Items, item, itemPath(path, i))
+	}
+	if s.Contains != nil {
+		contained := 0
+		for _, item := range array {
+			if v.valid(s.Contains, item) {
+				contained++
+			}
+		}
+		if contained == 0 && (s.MinContains == nil || *s.MinContains > 0) {
+			v.fail(path, "must contain a matching item")
+		}
+	}
+}
+
+// validateString validates the keywords applying to strings.
+func (v *validator) validateString(s *Schema, str string, path string) {
+	length := uint64(utf8.RuneCountInString(str))
+	if s.MinLength != nil && length < *s.MinLength {
+		v.fail(path, "must be at least %d characters long", *s.MinLength)
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		v.fail(path, "must be at most %d characters long", *s.MaxLength)
+	}
+	if s.Pattern != "" {
+		re, err := compilePattern(s.Pattern)
+		if err != nil {
+			v.fail(path, "invalid pattern %q: %v", s.Pattern, err)
+		} else if !re.MatchString(str) {
+			v.fail(path, "must match pattern %q", s.Pattern)
+		}
+	}
+	if s.Format != "" && !validFormat(s.Format, str) {
+		v.fail(path, "must be a valid %s", s.Format)
+	}
+}
+
+// validateNumber validates the keywords applying to numbers.
+func (v *validator) validateNumber(s *Schema, n float64, path string) {
+	if limit, ok := numberValue(s.Minimum); ok && n < limit {
+		v.fail(path, "must be >= %s", s.Minimum)
+	}
+	if limit, ok := numberValue(s.Maximum); ok && n > limit {
+		v.fail(path, "must be <= %s", s.Maximum)
+	}
+	if limit, ok := numberValue(s.ExclusiveMinimum); ok && n <= limit {
+		v.fail(path, "must be > %s", s.ExclusiveMinimum)
+	}
+	if limit, ok := numberValue(s.ExclusiveMaximum); ok && n >= limit {
+		v.fail(path, "must be < %s", s.ExclusiveMaximum)
+	}
+	if div, ok := numberValue(s.MultipleOf); ok && div != 0 {
+		q := n / div
+		if math.Abs(q-math.Round(q)) > 1e-9 {
+			v.fail(path, "must be a multiple of %s", s.MultipleOf)
+		}
+	}
+}
+
+// hasType reports whether the value is of the JSON schema type.
+func hasType(value any, typ string) bool {
+	switch typ {
+	case "null":
+		return value == nil
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := toFloat(value)
+		return ok
+	case "integer":
+		n, ok := toFloat(value)
+		return ok && n == math.Trunc(n) && !math.IsInf(n, 0)
+	default:
+		return true
+	}
+}
+
+// typeOf returns the JSON type name of the value.
+func typeOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	}
+	if _, ok := toFloat(value); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// toFloat converts a JSON number to a float64.
+func toFloat(value any) (float64, bool) {
+	switch n := value.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// numberValue returns the value of an optional schema number keyword.
+func numberValue(n json.Number) (float64, bool) {
+	if n == "" {
+		return 0, false
+	}
+	f, err := n.Float64()
+	return f, err == nil
+}
+
+// equalValues reports whether two JSON values are equal, comparing numbers
+// by value.
+func equalValues(a, b any) bool {
+	if x, ok := toFloat(a); ok {
+		y, ok := toFloat(b)
+		return ok && x == y
+	}
+	switch a := a.(type) {
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, av := range a {
+			bv, ok := b[k]
+			if !ok || !equalValues(av, bv) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equalValues(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// containsValue reports whether the values contain the value.
+func containsValue(values []any, value any) bool {
+	for _, v := range values {
+		if equalValues(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatValues formats values as a comma separated list of JSON values.
+func formatValues(values []any) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		b, err := json.Marshal(v)
+		if err != nil {
+			parts[i] = fmt.Sprint(v)
+			continue
+		}
+		parts[i] = string(b)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// compilePattern compiles a schema pattern, caching the result.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
+
+// validFormat reports whether the string is valid for the format.
+//
+// Unknown formats are treated as valid.
+func validFormat(format, str string) bool {
+	var err error
+	switch format {
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, str)
+	case "date":
+		_, err = time.Parse(time.DateOnly, str)
+	case "time":
+		_, err = time.Parse("15:04:05Z07:00", str)
+	case "email":
+		_, err = mail.ParseAddress(str)
+	case "ipv4":
+		ip := net.ParseIP(str)
+		return ip != nil && ip.To4() != nil && !strings.Contains(str, ":")
+	case "ipv6":
+		ip := net.ParseIP(str)
+		return ip != nil && strings.Contains(str, ":")
+	case "uri":
+		var u *url.URL
+		u, err = url.Parse(str)
+		return err == nil && u.Scheme != ""
+	}
+	return err == nil
+}
+
+// propertyPath returns the path of a property of the object at the path.
+func propertyPath(path, name string) string {
+	for _, r := range name {
+		if r != '_' && !('a' <= r && r <= 'z') && !('A' <= r && r <= 'Z') &&
+			!('0' <= r && r <= '9') {
+			return path + "[" + strconv.Quote(name) + "]"
+		}
+	}
+	if name == "" {
+		return path + `[""]`
+	}
+	return path + "." + name
+}
+
+// itemPath returns the path of an item of the array at the path.
+func itemPath(path string, index int) string {
+	return path + "[" + strconv.Itoa(index) + "]"
+}
@@ -0,0 +1,133 @@
+package jsonschema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	validateAddress struct {
+		Street string `json:"street" jsonschema:"minLength=1"`
+		Zip    string `json:"zip" jsonschema:"pattern=^[0-9]{5}$"`
+	}
+	validatePerson struct {
+		Name      string            `json:"name"`
+		Age       int               `json:"age" jsonschema:"minimum=0,maximum=150"`
+		Role      string            `json:"role" jsonschema:"enum=admin,enum=user"`
+		Email     string            `json:"email,omitempty" jsonschema:"format=email"`
+		Addresses []validateAddress `json:"addresses" jsonschema:"maxItems=2"`
+		Labels    map[string]int    `json:"labels,omitempty"`
+		Manager   *validatePerson   `json:"manager,omitempty"`
+	}
+)
+
+func violations(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var validationErr groqerr.ErrSchemaValidation
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected ErrSchemaValidation, got %v", err)
+	}
+	out := make([]string, len(validationErr.Violations))
+	for i, v := range validationErr.Violations {
+		out[i] = v.String()
+	}
+	return out
+}
+
+func TestValidate(t *testing.T) {
+	s, err := ReflectSchema(&validatePerson{})
+	assert.NoError(t, err)
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{
+			name: "valid",
+			data: `{"name":"Ada","age":36,"role":"admin","addresses":[{"street":"Main","zip":"12345"}],"labels":{"a":1},"manager":{"name":"Bob","age":50,"role":"user","addresses":[]}}`,
+		},
+		{
+			name: "required",
+			data: `{"name":"Ada","role":"user"}`,
+			want: []string{"$.age: is required", "$.addresses: is required"},
+		},
+		{
+			name: "bounds and enums",
+			data: `{"name":"Ada","age":200,"role":"root","addresses":[]}`,
+			want: []string{`$.age: must be <= 150`, `$.role: must be one of "admin", "user"`},
+		},
+		{
+			name: "types",
+			data: `{"name":1,"age":1.5,"role":"user","addresses":{}}`,
+			want: []string{
+				"$.addresses: expected array, got object",
+				"$.age: expected integer, got number",
+				"$.name: expected string, got number",
+			},
+		},
+		{
+			name: "nested refs",
+			data: `{"name":"Ada","age":1,"role":"user","addresses":[{"street":"","zip":"abc"},{"street":"a","zip":"00000"},{"street":"b","zip":"11111"}],"manager":{"name":"Bob","age":-1,"role":"user","addresses":[]}}`,
+			want: []string{
+				"$.addresses: must have at most 2 items",
+				"$.addresses[0].street: must be at least 1 characters long",
+				`$.addresses[0].zip: must match pattern "^[0-9]{5}$"`,
+				"$.manager.age: must be >= 0",
+			},
+		},
+		{
+			name: "additional properties",
+			data: `{"name":"Ada","age":1,"role":"user","addresses":[],"labels":{"a":"b"},"extra":true,"with space":1}`,
+			want: []string{
+				"$.extra: additional property is not allowed",
+				"$.labels.a: expected integer, got string",
+				`$["with space"]: additional property is not allowed`,
+			},
+		},
+		{
+			name: "format",
+			data: `{"name":"Ada","age":1,"role":"user","addresses":[],"email":"nope"}`,
+			want: []string{"$.email: must be a valid email"},
+		},
+		{
+			name: "invalid json",
+			data: `{"name":`,
+			want: []string{"$: invalid JSON: unexpected EOF"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, violations(t, s.Validate([]byte(tt.data))))
+		})
+	}
+}
+
+func TestValidateCombinators(t *testing.T) {
+	s := &Schema{
+		AnyOf: []*Schema{{Type: "string"}, {Type: "null"}},
+	}
+	assert.NoError(t, s.ValidateValue(nil))
+	assert.NoError(t, s.ValidateValue("a"))
+	assert.Equal(
+		t,
+		[]string{"$: must match at least one of the anyOf schemas"},
+		violations(t, s.ValidateValue(1)),
+	)
+	s = &Schema{
+		OneOf: []*Schema{{Type: "integer"}, {Type: "number"}},
+	}
+	assert.NoError(t, s.ValidateValue(1.5))
+	assert.Len(t, violations(t, s.ValidateValue(1)), 1)
+	s = &Schema{Type: "array", Items: &Schema{Type: "integer"}, UniqueItems: true}
+	assert.Equal(
+		t,
+		[]string{"$[2]: duplicates item 0"},
+		violations(t, s.Validate([]byte(`[1,2,1.0]`))),
+	)
+}
@@ -0,0 +1,12 @@
+// Package otelgroq provides OpenTelemetry tracing and metrics for groq-go
+// following the GenAI semantic conventions.
+//
+// It is a separate module so that the core module does not depend on
+// OpenTelemetry. The instrumentation is a groq.Middleware:
+//
+//	mw, err := otelgroq.Middleware()
+//	if err != nil {
+//		return err
+//	}
+//	client, err := groq.NewClient(key, groq.WithMiddleware(mw))
+package otelgroq
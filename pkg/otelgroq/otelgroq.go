@@ -0,0 +1,396 @@
+package otelgroq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/conneroisu/groq-go/pkg/tools"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ScopeName is the instrumentation scope name of the tracer and meter.
+	ScopeName = "github.com/conneroisu/groq-go/pkg/otelgroq"
+	// providerName is the value of the gen_ai.provider.name attribute.
+	providerName = "groq"
+)
+
+// Attribute keys of the GenAI semantic conventions.
+const (
+	keyOperationName     = attribute.Key("gen_ai.operation.name")
+	keyProviderName      = attribute.Key("gen_ai.provider.name")
+	keyRequestModel      = attribute.Key("gen_ai.request.model")
+	keyRequestMaxTokens  = attribute.Key("gen_ai.request.max_tokens")
+	keyRequestTemp       = attribute.Key("gen_ai.request.temperature")
+	keyRequestTopP       = attribute.Key("gen_ai.request.top_p")
+	keyResponseID        = attribute.Key("gen_ai.response.id")
+	keyResponseModel     = attribute.Key("gen_ai.response.model")
+	keyFinishReasons     = attribute.Key("gen_ai.response.finish_reasons")
+	keyTimeToFirstChunk  = attribute.Key("gen_ai.response.time_to_first_chunk")
+	keyInputTokens       = attribute.Key("gen_ai.usage.input_tokens")
+	keyOutputTokens      = attribute.Key("gen_ai.usage.output_tokens")
+	keyTokenType         = attribute.Key("gen_ai.token.type")
+	keyToolName          = attribute.Key("gen_ai.tool.name")
+	keyToolCallID        = attribute.Key("gen_ai.tool.call.id")
+	keyErrorType         = attribute.Key("error.type")
+	tokenTypeInput       = "input"
+	tokenTypeOutput      = "output"
+	operationChat        = "chat"
+	operationEmbeddings  = "embeddings"
+	operationExecuteTool = "execute_tool"
+)
+
+type (
+	// Option is an option for the instrumentation.
+	Option func(*config)
+	// config is the configuration of the instrumentation.
+	config struct {
+		tracerProvider trace.TracerProvider
+		meterProvider  metric.MeterProvider
+	}
+	// instrumentation holds the tracer and instruments recording the
+	// calls of a client.
+	instrumentation struct {
+		tracer           trace.Tracer
+		duration         metric.Float64Histogram
+		tokenUsage       metric.Int64Histogram
+		timeToFirstChunk metric.Float64Histogram
+		requests         metric.Int64Counter
+		tokens           metric.Int64Counter
+	}
+	// record is the telemetry of a single call.
+	record struct {
+		inst      *instrumentation
+		ctx       context.Context
+		span      trace.Span
+		start     time.Time
+		mu        sync.Mutex
+		attrs     []attribute.KeyValue
+		endOnce   sync.Once
+		firstOnce sync.Once
+	}
+)
+
+// WithTracerProvider sets the tracer provider of the instrumentation.
+//
+// It defaults to the global tracer provider.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) { c.tracerProvider = provider }
+}
+
+// WithMeterProvider sets the meter provider of the instrumentation.
+//
+// It defaults to the global meter provider.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) { c.meterProvider = provider }
+}
+
+// Middleware returns a groq.Middleware recording a span and metrics for
+// every call of a client or extension.
+//
+// Chat completions record their model, finish reasons and token usage;
+// streams additionally record the time to their first chunk and end their
+// span once they are read to the end or closed. Tool calls run by a
+// groq.Agent are recorded as execute_tool spans.
+func Middleware(opts ...Option) (groq.Middleware, error) {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	inst, err := newInstrumentation(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return inst.middleware, nil
+}
+
+// newInstrumentation creates the tracer and instruments of the
+// configuration.
+func newInstrumentation(cfg config) (*instrumentation, error) {
+	meter := cfg.meterProvider.Meter(ScopeName)
+	inst := &instrumentation{tracer: cfg.tracerProvider.Tracer(ScopeName)}
+	var err error
+	inst.duration, err = meter.Float64Histogram(
+		"gen_ai.client.operation.duration",
+		metric.WithDescription("Duration of GenAI operations."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create duration histogram: %w", err)
+	}
+	inst.tokenUsage, err = meter.Int64Histogram(
+		"gen_ai.client.token.usage",
+		metric.WithDescription("Number of input and output tokens used per operation."),
+		metric.WithUnit("{token}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token usage histogram: %w", err)
+	}
+	inst.timeToFirstChunk, err = meter.Float64Histogram(
+		"gen_ai.client.operation.time_to_first_chunk",
+		metric.WithDescription("Time to receive the first chunk of a stream."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create time to first chunk histogram: %w", err)
+	}
+	inst.requests, err = meter.Int64Counter(
+		"groq.client.requests",
+		metric.WithDescription("Number of operations made."),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create requests counter: %w", err)
+	}
+	inst.tokens, err = meter.Int64Counter(
+		"groq.client.tokens",
+		metric.WithDescription("Number of input and output tokens used."),
+		metric.WithUnit("{token}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tokens counter: %w", err)
+	}
+	return inst, nil
+}
+
+// middleware records the telemetry of a call.
+func (i *instrumentation) middleware(next groq.Handler) groq.Handler {
+	return func(ctx context.Context, call *groq.Call) (any, error) {
+		rec := i.start(ctx, call)
+		res, err := next(rec.ctx, call)
+		if err != nil {
+			rec.end(err)
+			return res, err
+		}
+		switch response := res.(type) {
+		case groq.ChatCompletionResponse:
+			rec.chatResponse(response)
+		case *groq.ChatCompletionStream:
+			rec.observe(response)
+			return res, err
+		case groq.EmbeddingResponse:
+			rec.usage(response.Usage.PromptTokens, 0)
+		}
+		rec.end(nil)
+		return res, err
+	}
+}
+
+// start starts the span of a call.
+func (i *instrumentation) start(ctx context.Context, call *groq.Call) *record {
+	operation := operationName(call.Operation)
+	attrs := []attribute.KeyValue{
+		keyOperationName.String(operation),
+		keyProviderName.String(providerName),
+	}
+	if call.Model != "" {
+		attrs = append(attrs, keyRequestModel.String(call.Model))
+	}
+	spanAttrs := requestAttributes(call)
+	name := operation
+	kind := trace.SpanKindClient
+	if tc, ok := call.Request.(*tools.ToolCall); ok {
+		name += " " + tc.Function.Name
+		kind = trace.SpanKindInternal
+	} else if call.Model != "" {
+		name += " " + call.Model
+	}
+	ctx, span := i.tracer.Start(
+		ctx,
+		name,
+		trace.WithSpanKind(kind),
+		trace.WithAttributes(append(spanAttrs, attrs...)...),
+	)
+	return &record{
+		inst:  i,
+		ctx:   ctx,
+		span:  span,
+		start: time.Now(),
+		attrs: attrs,
+	}
+}
+
+// requestAttributes returns the span attributes of the typed request of a
+// call.
+func requestAttributes(call *groq.Call) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	switch request := call.Request.(type) {
+	case *groq.ChatCompletionRequest:
+		if request.MaxTokens > 0 {
+			attrs = append(attrs, keyRequestMaxTokens.Int(request.MaxTokens))
+		}
+		if request.Temperature != 0 {
+			attrs = append(attrs, keyRequestTemp.Float64(float64(request.Temperature)))
+		}
+		if request.TopP != 0 {
+			attrs = append(attrs, keyRequestTopP.Float64(float64(request.TopP)))
+		}
+	case *tools.ToolCall:
+		attrs = append(attrs,
+			keyToolName.String(request.Function.Name),
+			keyToolCallID.String(request.ID),
+		)
+	}
+	return attrs
+}
+
+// chatResponse records the model, finish reasons and usage of a chat
+// completion.
+func (r *record) chatResponse(response groq.ChatCompletionResponse) {
+	reasons := make([]string, 0, len(response.Choices))
+	for _, choice := range response.Choices {
+		reasons = append(reasons, string(choice.FinishReason))
+	}
+	r.response(response.ID, string(response.Model), reasons)
+	r.usage(response.Usage.PromptTokens, response.Usage.CompletionTokens)
+}
+
+// observe records the chunks of a stream and ends the span of the call
+// once the stream ends or is closed.
+func (r *record) observe(stream *groq.ChatCompletionStream) {
+	var reasons []string
+	stream.Observe(
+		func(chunk *groq.ChatCompletionStreamResponse, err error) {
+			if err != nil {
+				r.response("", "", reasons)
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+				r.end(err)
+				return
+			}
+			r.firstOnce.Do(func() {
+				elapsed := time.Since(r.start).Seconds()
+				r.span.SetAttributes(keyTimeToFirstChunk.Float64(elapsed))
+				r.response(chunk.ID, string(chunk.Model), nil)
+				r.inst.timeToFirstChunk.Record(
+					r.ctx,
+					elapsed,
+					r.metricAttributes(),
+				)
+			})
+			for _, choice := range chunk.Choices {
+				if choice.FinishReason != "" {
+					reasons = append(reasons, string(choice.FinishReason))
+				}
+			}
+			usage := chunk.Usage
+			if chunk.XGroq != nil && chunk.XGroq.Usage != nil {
+				usage = chunk.XGroq.Usage
+			}
+			if usage != nil {
+				r.usage(usage.PromptTokens, usage.CompletionTokens)
+			}
+		},
+		func() { r.end(nil) },
+	)
+}
+
+// response records the id, model and finish reasons of a response.
+func (r *record) response(id, model string, reasons []string) {
+	if id != "" {
+		r.span.SetAttributes(keyResponseID.String(id))
+	}
+	if model != "" {
+		r.span.SetAttributes(keyResponseModel.String(model))
+		r.mu.Lock()
+		r.attrs = append(r.attrs, keyResponseModel.String(model))
+		r.mu.Unlock()
+	}
+	if len(reasons) > 0 {
+		r.span.SetAttributes(keyFinishReasons.StringSlice(reasons))
+	}
+}
+
+// usage records the input and output tokens of a call.
+func (r *record) usage(input, output int) {
+	r.span.SetAttributes(
+		keyInputTokens.Int(input),
+		keyOutputTokens.Int(output),
+	)
+	for tokenType, tokens := range map[string]int{
+		tokenTypeInput:  input,
+		tokenTypeOutput: output,
+	} {
+		if tokens == 0 {
+			continue
+		}
+		attrs := r.metricAttributes(keyTokenType.String(tokenType))
+		r.inst.tokenUsage.Record(r.ctx, int64(tokens), attrs)
+		r.inst.tokens.Add(r.ctx, int64(tokens), attrs)
+	}
+}
+
+// end records the duration and error of a call and ends its span.
+//
+// Only the first call of end has an effect.
+func (r *record) end(err error) {
+	r.endOnce.Do(func() {
+		var extra []attribute.KeyValue
+		if err != nil {
+			errType := fmt.Sprintf("%T", err)
+			extra = append(extra, keyErrorType.String(errType))
+			r.span.SetAttributes(keyErrorType.String(errType))
+			r.span.RecordError(err)
+			r.span.SetStatus(codes.Error, err.Error())
+		}
+		attrs := r.metricAttributes(extra...)
+		r.inst.duration.Record(r.ctx, time.Since(r.start).Seconds(), attrs)
+		r.inst.requests.Add(r.ctx, 1, attrs)
+		r.span.End()
+	})
+}
+
+// metricAttributes returns the metric attributes of the call with the
+// extra attributes.
+func (r *record) metricAttributes(
+	extra ...attribute.KeyValue,
+) metric.MeasurementOption {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attrs := make([]attribute.KeyValue, 0, len(r.attrs)+len(extra))
+	return metric.WithAttributes(append(append(attrs, r.attrs...), extra...)...)
+}
+
+// operationName returns the GenAI operation name of a client operation.
+//
+// Operations without a GenAI equivalent are converted to snake case while
+// the operations of the extensions are kept as is.
+func operationName(operation string) string {
+	if strings.ContainsRune(operation, ' ') {
+		return operation
+	}
+	switch operation {
+	case "ChatCompletion", "ChatCompletionStream":
+		return operationChat
+	case "Embeddings":
+		return operationEmbeddings
+	case "ExecuteTool":
+		return operationExecuteTool
+	}
+	var b strings.Builder
+	for i, r := range operation {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
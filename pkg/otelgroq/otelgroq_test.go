@@ -0,0 +1,210 @@
+package otelgroq_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/conneroisu/groq-go/pkg/otelgroq"
+	"github.com/conneroisu/groq-go/pkg/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// setup returns a client instrumented with in-memory exporters talking to
+// the handler.
+func setup(
+	t *testing.T,
+	handler http.HandlerFunc,
+) (*groq.Client, *tracetest.InMemoryExporter, *sdkmetric.ManualReader) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	spans := tracetest.NewInMemoryExporter()
+	reader := sdkmetric.NewManualReader()
+	mw, err := otelgroq.Middleware(
+		otelgroq.WithTracerProvider(sdktrace.NewTracerProvider(
+			sdktrace.WithSyncer(spans),
+		)),
+		otelgroq.WithMeterProvider(sdkmetric.NewMeterProvider(
+			sdkmetric.WithReader(reader),
+		)),
+	)
+	require.NoError(t, err)
+	client, err := groq.NewClient(
+		"key",
+		groq.WithBaseURL(server.URL),
+		groq.WithRetryPolicy(groq.NoRetryPolicy()),
+		groq.WithMiddleware(mw),
+	)
+	require.NoError(t, err)
+	return client, spans, reader
+}
+
+// attributes returns the attributes of a span as a map.
+func attributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+// metrics returns the collected metrics keyed by name.
+func metrics(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	data := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			data[m.Name] = m.Data
+		}
+	}
+	return data
+}
+
+// TestChatCompletionSpan tests the span and metrics of a chat completion.
+func TestChatCompletionSpan(t *testing.T) {
+	a := assert.New(t)
+	client, spans, reader := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(groq.ChatCompletionResponse{
+			ID:    "chatcmpl-1",
+			Model: groq.ModelLlama38B8192,
+			Choices: []groq.ChatCompletionChoice{{
+				Message:      groq.ChatCompletionMessage{Role: groq.RoleAssistant, Content: "hi"},
+				FinishReason: groq.ReasonStop,
+			}},
+			Usage: groq.Usage{PromptTokens: 12, CompletionTokens: 3, TotalTokens: 15},
+		})
+	})
+	_, err := client.ChatCompletion(context.Background(), groq.ChatCompletionRequest{
+		Model:     groq.ModelLlama38B8192,
+		Messages:  []groq.ChatCompletionMessage{{Role: groq.RoleUser, Content: "hi"}},
+		MaxTokens: 100,
+	})
+	require.NoError(t, err)
+	stubs := spans.GetSpans()
+	require.Len(t, stubs, 1)
+	a.Equal("chat llama3-8b-8192", stubs[0].Name)
+	attrs := attributes(stubs[0])
+	a.Equal("chat", attrs["gen_ai.operation.name"].AsString())
+	a.Equal("groq", attrs["gen_ai.provider.name"].AsString())
+	a.Equal("llama3-8b-8192", attrs["gen_ai.request.model"].AsString())
+	a.Equal(int64(100), attrs["gen_ai.request.max_tokens"].AsInt64())
+	a.Equal("chatcmpl-1", attrs["gen_ai.response.id"].AsString())
+	a.Equal([]string{"stop"}, attrs["gen_ai.response.finish_reasons"].AsStringSlice())
+	a.Equal(int64(12), attrs["gen_ai.usage.input_tokens"].AsInt64())
+	a.Equal(int64(3), attrs["gen_ai.usage.output_tokens"].AsInt64())
+
+	data := metrics(t, reader)
+	usage := data["gen_ai.client.token.usage"].(metricdata.Histogram[int64])
+	a.Len(usage.DataPoints, 2)
+	duration := data["gen_ai.client.operation.duration"].(metricdata.Histogram[float64])
+	a.Equal(uint64(1), duration.DataPoints[0].Count)
+	tokens := data["groq.client.tokens"].(metricdata.Sum[int64])
+	var total int64
+	for _, dp := range tokens.DataPoints {
+		total += dp.Value
+	}
+	a.Equal(int64(15), total)
+}
+
+// TestChatCompletionStreamSpan tests that a stream span records the time to
+// the first chunk and ends with the stream.
+func TestChatCompletionStreamSpan(t *testing.T) {
+	a := assert.New(t)
+	client, spans, reader := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		chunks := []groq.ChatCompletionStreamResponse{
+			{ID: "chatcmpl-2", Model: groq.ModelLlama38B8192, Choices: []groq.ChatCompletionStreamChoice{{
+				Delta: groq.ChatCompletionStreamChoiceDelta{Content: "hel"},
+			}}},
+			{ID: "chatcmpl-2", Model: groq.ModelLlama38B8192, Choices: []groq.ChatCompletionStreamChoice{{
+				Delta:        groq.ChatCompletionStreamChoiceDelta{Content: "lo"},
+				FinishReason: groq.ReasonStop,
+			}}, XGroq: &groq.XGroq{Usage: &groq.Usage{PromptTokens: 5, CompletionTokens: 2}}},
+		}
+		for _, chunk := range chunks {
+			b, _ := json.Marshal(chunk)
+			_, _ = fmt.Fprintf(w, "data: %s\n\n", b)
+		}
+		_, _ = io.WriteString(w, "data: [DONE]\n\n")
+	})
+	stream, err := client.ChatCompletionStream(context.Background(), groq.ChatCompletionRequest{
+		Model:    groq.ModelLlama38B8192,
+		Messages: []groq.ChatCompletionMessage{{Role: groq.RoleUser, Content: "hi"}},
+	})
+	require.NoError(t, err)
+	a.Empty(spans.GetSpans())
+	response, err := stream.Accumulate()
+	require.NoError(t, err)
+	a.Equal("hello", response.Choices[0].Message.Content)
+	stubs := spans.GetSpans()
+	require.Len(t, stubs, 1)
+	attrs := attributes(stubs[0])
+	a.Contains(attrs, attribute.Key("gen_ai.response.time_to_first_chunk"))
+	a.Equal([]string{"stop"}, attrs["gen_ai.response.finish_reasons"].AsStringSlice())
+	a.Equal(int64(5), attrs["gen_ai.usage.input_tokens"].AsInt64())
+	a.Equal(int64(2), attrs["gen_ai.usage.output_tokens"].AsInt64())
+	a.Contains(metrics(t, reader), "gen_ai.client.operation.time_to_first_chunk")
+}
+
+// TestErrorAndToolSpans tests the spans of failed calls and of the tool
+// calls of an agent.
+func TestErrorAndToolSpans(t *testing.T) {
+	a := assert.New(t)
+	calls := 0
+	client, spans, _ := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		message := groq.ChatCompletionMessage{Role: groq.RoleAssistant, Content: "done"}
+		if calls == 1 {
+			message = groq.ChatCompletionMessage{
+				Role: groq.RoleAssistant,
+				ToolCalls: []tools.ToolCall{{
+					ID:       "call_1",
+					Type:     "function",
+					Function: tools.FunctionCall{Name: "lookup", Arguments: "{}"},
+				}},
+			}
+		}
+		_ = json.NewEncoder(w).Encode(groq.ChatCompletionResponse{
+			Choices: []groq.ChatCompletionChoice{{Message: message}},
+		})
+	})
+	agent := groq.NewAgent(client, groq.WithToolHandler(
+		tools.Tool{
+			Type:     tools.ToolTypeFunction,
+			Function: tools.FunctionDefinition{Name: "lookup"},
+		},
+		func(context.Context, tools.ToolCall) (string, error) {
+			return "", errors.New("lookup failed")
+		},
+	))
+	_, err := agent.Run(context.Background(), groq.ChatCompletionRequest{
+		Model:    groq.ModelLlama38B8192,
+		Messages: []groq.ChatCompletionMessage{{Role: groq.RoleUser, Content: "hi"}},
+	})
+	require.NoError(t, err)
+	stubs := spans.GetSpans()
+	require.Len(t, stubs, 3)
+	tool := stubs[1]
+	a.Equal("execute_tool lookup", tool.Name)
+	a.Equal(codes.Error, tool.Status.Code)
+	attrs := attributes(tool)
+	a.Equal("lookup", attrs["gen_ai.tool.name"].AsString())
+	a.Equal("call_1", attrs["gen_ai.tool.call.id"].AsString())
+	a.Contains(attrs, attribute.Key("error.type"))
+}
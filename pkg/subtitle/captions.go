@@ -0,0 +1,195 @@
+package subtitle
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/conneroisu/groq-go"
+)
+
+type (
+	// Opts is a function that sets options for the captions of
+	// Captions, FromWords and Wrap.
+	Opts func(*config)
+	// config is the configuration of captions.
+	config struct {
+		maxLineLength int
+		maxLines      int
+		maxDuration   time.Duration
+		maxGap        time.Duration
+	}
+)
+
+// WithMaxLineLength sets the number of characters of the lines of the
+// captions, 42 by default. Words longer than the lines keep a line of their
+// own.
+func WithMaxLineLength(n int) Opts {
+	return func(c *config) { c.maxLineLength = n }
+}
+
+// WithMaxLines sets the number of lines of a caption, 2 by default.
+func WithMaxLines(n int) Opts {
+	return func(c *config) { c.maxLines = max(1, n) }
+}
+
+// WithMaxDuration sets the longest duration of the captions re-segmented
+// from words, 7 seconds by default.
+func WithMaxDuration(d time.Duration) Opts {
+	return func(c *config) { c.maxDuration = d }
+}
+
+// WithMaxGap sets the longest pause between the words of a caption
+// re-segmented from words, 1 second by default.
+func WithMaxGap(d time.Duration) Opts {
+	return func(c *config) { c.maxGap = d }
+}
+
+// newConfig returns the configuration of the options.
+func newConfig(opts []Opts) config {
+	cfg := config{
+		maxLineLength: 42,
+		maxLines:      2,
+		maxDuration:   7 * time.Second,
+		maxGap:        time.Second,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// Captions returns the captions of a transcription, re-segmented from its
+// words when it has word timestamps and wrapped from its segments
+// otherwise.
+func Captions(response groq.AudioResponse, opts ...Opts) groq.Segments {
+	if len(response.Words) > 0 {
+		return FromWords(response.Words, opts...)
+	}
+	return Wrap(response.Segments, opts...)
+}
+
+// FromWords groups timed words into captions.
+//
+// A caption ends after a word ending a sentence, before a pause longer than
+// the maximum gap and before the word that would make it last longer than
+// the maximum duration or overflow its lines.
+func FromWords(words groq.Words, opts ...Opts) groq.Segments {
+	cfg := newConfig(opts)
+	var (
+		segments   groq.Segments
+		text       string
+		start, end float64
+	)
+	flush := func() {
+		if text != "" {
+			lines := wrap(text, cfg.maxLineLength)
+			segments = appendSegment(segments, start, end, strings.Join(lines, "\n"))
+			text = ""
+		}
+	}
+	for _, word := range words {
+		w := strings.TrimSpace(word.Word)
+		if w == "" {
+			continue
+		}
+		if text != "" && (endsSentence(text) ||
+			word.Start-end > cfg.maxGap.Seconds() ||
+			word.End-start > cfg.maxDuration.Seconds() ||
+			len(wrap(text+" "+w, cfg.maxLineLength)) > cfg.maxLines) {
+			flush()
+		}
+		if text == "" {
+			text, start = w, word.Start
+		} else {
+			text += " " + w
+		}
+		end = word.End
+	}
+	flush()
+	return segments
+}
+
+// Wrap wraps the text of the segments into lines and splits the segments
+// having more lines than a caption into several captions, timed in
+// proportion to their number of characters.
+func Wrap(segments groq.Segments, opts ...Opts) groq.Segments {
+	cfg := newConfig(opts)
+	var wrapped groq.Segments
+	for _, segment := range segments {
+		lines := wrap(strings.Join(strings.Fields(segment.Text), " "), cfg.maxLineLength)
+		if len(lines) == 0 {
+			continue
+		}
+		total := 0
+		for _, line := range lines {
+			total += utf8.RuneCountInString(line)
+		}
+		duration := segment.End - segment.Start
+		before := 0
+		for i := 0; i < len(lines); i += cfg.maxLines {
+			group := lines[i:min(i+cfg.maxLines, len(lines))]
+			chars := 0
+			for _, line := range group {
+				chars += utf8.RuneCountInString(line)
+			}
+			caption := segment
+			caption.ID = len(wrapped)
+			caption.Start = segment.Start + duration*float64(before)/float64(total)
+			caption.End = segment.Start + duration*float64(before+chars)/float64(total)
+			caption.Text = strings.Join(group, "\n")
+			if len(lines) > cfg.maxLines {
+				// the tokens of the segment are not split with its text
+				caption.Tokens = nil
+			}
+			wrapped = append(wrapped, caption)
+			before += chars
+		}
+	}
+	return wrapped
+}
+
+// wrap greedily wraps the words of the text into lines of at most width
+// characters, or into a single line when width is not positive.
+func wrap(text string, width int) []string {
+	var (
+		lines []string
+		line  string
+	)
+	for _, word := range strings.Fields(text) {
+		switch {
+		case line == "":
+			line = word
+		case width <= 0 ||
+			utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// endsSentence reports whether the text ends with the punctuation ending a
+// sentence, ignoring closing quotes and brackets.
+func endsSentence(text string) bool {
+	text = strings.TrimRight(text, `"')]»”’`)
+	return strings.HasSuffix(text, ".") || strings.HasSuffix(text, "!") ||
+		strings.HasSuffix(text, "?") || strings.HasSuffix(text, "…")
+}
+
+// appendSegment appends a segment of the timing and text to the segments,
+// numbered after them.
+func appendSegment(segments groq.Segments, start, end float64, text string) groq.Segments {
+	segments = append(segments, make(groq.Segments, 1)...)
+	segment := &segments[len(segments)-1]
+	segment.ID = len(segments) - 1
+	segment.Start = start
+	segment.End = end
+	segment.Text = text
+	return segments
+}
@@ -0,0 +1,20 @@
+// Package subtitle converts the transcriptions of the groq-go client to and
+// from SRT and WebVTT subtitles.
+//
+// Subtitles are parsed into groq.Segments and segments are rendered back
+// into either format, so a single verbose_json transcription produces every
+// caption format:
+//
+//	response, err := client.Transcribe(ctx, groq.AudioRequest{
+//		Model:    groq.ModelWhisperLargeV3,
+//		FilePath: "talk.mp3",
+//		Format:   groq.FormatVerboseJSON,
+//	})
+//	captions := subtitle.Captions(response, subtitle.WithMaxLineLength(32))
+//	srt, vtt := subtitle.SRT(captions), subtitle.VTT(captions)
+//
+// Captions are re-segmented from the word timings of the response when it
+// has them, and otherwise its segments are wrapped into cues of limited
+// lines. Shift and Scale move the timestamps of the segments, for example
+// to synchronize them with an edited video.
+package subtitle
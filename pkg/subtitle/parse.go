@@ -0,0 +1,180 @@
+package subtitle
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/conneroisu/groq-go"
+)
+
+// vttTag matches the tags of the text of vtt cues, such as voices, classes
+// and timestamps.
+var vttTag = regexp.MustCompile(`<[^>]*>`)
+
+// Parse parses SRT or WebVTT subtitles into segments, telling them apart
+// by the WEBVTT header of WebVTT.
+func Parse(text string) (groq.Segments, error) {
+	if strings.HasPrefix(normalize(text), "WEBVTT") {
+		return ParseVTT(text)
+	}
+	return ParseSRT(text)
+}
+
+// ParseSRT parses SRT subtitles into segments.
+//
+// The lines of the text of a cue are joined with newlines and the segments
+// are numbered from zero, whatever the indices of the cues.
+func ParseSRT(text string) (groq.Segments, error) {
+	var segments groq.Segments
+	for _, block := range blocks(normalize(text)) {
+		lines := block.lines
+		if len(lines) > 0 && !strings.Contains(lines[0], "-->") {
+			if _, err := strconv.Atoi(strings.TrimSpace(lines[0])); err != nil {
+				return nil, fmt.Errorf(
+					"line %d: invalid srt cue index %q",
+					block.line, lines[0],
+				)
+			}
+			lines = lines[1:]
+		}
+		start, end, err := parseTiming(lines, block.line)
+		if err != nil {
+			return nil, err
+		}
+		segments = appendSegment(segments, start, end, strings.Join(lines[1:], "\n"))
+	}
+	return segments, nil
+}
+
+// ParseVTT parses WebVTT subtitles into segments.
+//
+// Notes, styles, regions, cue identifiers and cue settings are dropped, and
+// the tags of the text of the cues are removed with its character
+// references unescaped.
+func ParseVTT(text string) (groq.Segments, error) {
+	parsed := blocks(normalize(text))
+	if len(parsed) == 0 || !isVTTHeader(parsed[0].lines[0]) {
+		return nil, errors.New("vtt subtitles are missing their WEBVTT header")
+	}
+	var segments groq.Segments
+	for _, block := range parsed[1:] {
+		lines := block.lines
+		switch strings.SplitN(lines[0], " ", 2)[0] {
+		case "NOTE", "STYLE", "REGION":
+			continue
+		}
+		if !strings.Contains(lines[0], "-->") {
+			// the cue identifier
+			lines = lines[1:]
+		}
+		start, end, err := parseTiming(lines, block.line)
+		if err != nil {
+			return nil, err
+		}
+		text := make([]string, 0, len(lines)-1)
+		for _, line := range lines[1:] {
+			text = append(text, html.UnescapeString(vttTag.ReplaceAllString(line, "")))
+		}
+		segments = appendSegment(segments, start, end, strings.Join(text, "\n"))
+	}
+	return segments, nil
+}
+
+// block is a block of subtitles separated from the others by blank lines.
+type block struct {
+	// line is the line number of the first line of the block.
+	line  int
+	lines []string
+}
+
+// normalize returns the text without byte order mark and with unix line
+// endings.
+func normalize(text string) string {
+	text = strings.TrimPrefix(text, "\ufeff")
+	return strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\r", "\n")
+}
+
+// blocks splits the text into its blocks.
+func blocks(text string) []block {
+	var (
+		parsed  []block
+		current *block
+	)
+	for i, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			current = nil
+			continue
+		}
+		if current == nil {
+			parsed = append(parsed, block{line: i + 1})
+			current = &parsed[len(parsed)-1]
+		}
+		current.lines = append(current.lines, line)
+	}
+	return parsed
+}
+
+// isVTTHeader reports whether the line is the header of vtt subtitles.
+func isVTTHeader(line string) bool {
+	rest, ok := strings.CutPrefix(line, "WEBVTT")
+	return ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t')
+}
+
+// parseTiming parses the timing line starting the lines of a cue of the
+// block at the line number.
+func parseTiming(lines []string, line int) (start, end float64, err error) {
+	if len(lines) == 0 {
+		return 0, 0, fmt.Errorf("line %d: cue is missing its timing", line)
+	}
+	from, to, ok := strings.Cut(lines[0], "-->")
+	if !ok {
+		return 0, 0, fmt.Errorf("line %d: invalid cue timing %q", line, lines[0])
+	}
+	// cue settings follow the end of vtt cues
+	fields := strings.Fields(to)
+	if len(fields) == 0 {
+		return 0, 0, fmt.Errorf("line %d: invalid cue timing %q", line, lines[0])
+	}
+	if start, err = parseTimestamp(strings.TrimSpace(from)); err != nil {
+		return 0, 0, fmt.Errorf("line %d: %w", line, err)
+	}
+	if end, err = parseTimestamp(fields[0]); err != nil {
+		return 0, 0, fmt.Errorf("line %d: %w", line, err)
+	}
+	return start, end, nil
+}
+
+// parseTimestamp parses a timestamp of hours, minutes, seconds and
+// milliseconds in seconds.
+//
+// The hours are optional and the milliseconds are separated by a comma or
+// a period, so that both srt and vtt timestamps are accepted.
+func parseTimestamp(s string) (float64, error) {
+	invalid := fmt.Errorf("invalid timestamp %q", s)
+	clock, fraction, _ := strings.Cut(strings.Replace(s, ",", ".", 1), ".")
+	parts := strings.Split(clock, ":")
+	if len(parts) < 2 || len(parts) > 3 || len(fraction) > 9 {
+		return 0, invalid
+	}
+	var seconds float64
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || (i > 0 && n > 59) {
+			return 0, invalid
+		}
+		seconds = seconds*60 + float64(n)
+	}
+	if fraction != "" {
+		n, err := strconv.Atoi(fraction)
+		if err != nil || n < 0 {
+			return 0, invalid
+		}
+		seconds += float64(n) / math.Pow10(len(fraction))
+	}
+	return seconds, nil
+}
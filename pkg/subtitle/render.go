@@ -0,0 +1,101 @@
+package subtitle
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/conneroisu/groq-go"
+)
+
+// vttEscaper escapes the characters of the text of vtt cues that would be
+// read as tags or character references.
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Render renders the segments as subtitles of the srt or vtt format.
+func Render(format groq.Format, segments groq.Segments) (string, error) {
+	switch format {
+	case groq.FormatSRT:
+		return SRT(segments), nil
+	case groq.FormatVTT:
+		return VTT(segments), nil
+	default:
+		return "", fmt.Errorf("unsupported subtitle format: %s", format)
+	}
+}
+
+// SRT renders the segments as SRT subtitles.
+//
+// Cues are numbered from one and segments without text are left out.
+func SRT(segments groq.Segments) string {
+	var b strings.Builder
+	index := 0
+	for _, segment := range segments {
+		text := cueText(segment.Text)
+		if text == "" {
+			continue
+		}
+		index++
+		if index > 1 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(strconv.Itoa(index))
+		b.WriteByte('\n')
+		b.WriteString(timestamp(segment.Start, ','))
+		b.WriteString(" --> ")
+		b.WriteString(timestamp(segment.End, ','))
+		b.WriteByte('\n')
+		b.WriteString(text)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// VTT renders the segments as WebVTT subtitles.
+//
+// Segments without text are left out.
+func VTT(segments groq.Segments) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n")
+	for _, segment := range segments {
+		text := cueText(segment.Text)
+		if text == "" {
+			continue
+		}
+		b.WriteByte('\n')
+		b.WriteString(timestamp(segment.Start, '.'))
+		b.WriteString(" --> ")
+		b.WriteString(timestamp(segment.End, '.'))
+		b.WriteByte('\n')
+		b.WriteString(vttEscaper.Replace(text))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// cueText returns the text of a segment as the lines of a cue, trimmed and
+// without blank lines, which would end the cue.
+func cueText(text string) string {
+	var lines []string
+	for _, line := range strings.Split(normalize(text), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// timestamp formats seconds as hours, minutes, seconds and milliseconds
+// separated by the separator, negative seconds being formatted as zero.
+func timestamp(seconds float64, separator byte) string {
+	ms := int64(math.Round(max(0, seconds) * 1000))
+	return fmt.Sprintf(
+		"%02d:%02d:%02d%c%03d",
+		ms/3_600_000,
+		ms/60_000%60,
+		ms/1000%60,
+		separator,
+		ms%1000,
+	)
+}
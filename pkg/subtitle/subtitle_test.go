@@ -0,0 +1,172 @@
+package subtitle_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/conneroisu/groq-go/pkg/subtitle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const srtText = "1\r\n" +
+	"00:00:01,000 --> 00:00:02,500\r\n" +
+	"Hello there.\r\n" +
+	"\r\n" +
+	"2\r\n" +
+	"01:02:03,045 --> 01:02:04,000\r\n" +
+	"Two\r\n" +
+	"lines\r\n"
+
+const vttText = `WEBVTT - a talk
+
+NOTE the first cue has an identifier
+
+STYLE
+::cue { color: white }
+
+intro
+00:01.000 --> 00:02.500 align:start position:10%
+<v Speaker>Hello &amp; welcome</v>
+
+01:02:03.045 --> 01:02:04.000
+Two
+<i>lines</i>
+`
+
+// TestParse tests that srt and vtt subtitles are parsed into segments.
+func TestParse(t *testing.T) {
+	a := assert.New(t)
+	for name, text := range map[string]string{"srt": srtText, "vtt": vttText} {
+		segments, err := subtitle.Parse(text)
+		require.NoError(t, err, name)
+		require.Len(t, segments, 2, name)
+		a.Equal(0, segments[0].ID)
+		a.InDelta(1.0, segments[0].Start, 1e-9, name)
+		a.InDelta(2.5, segments[0].End, 1e-9, name)
+		a.Equal(1, segments[1].ID)
+		a.InDelta(3723.045, segments[1].Start, 1e-9, name)
+		a.InDelta(3724.0, segments[1].End, 1e-9, name)
+		a.Equal("Two\nlines", segments[1].Text, name)
+	}
+	segments, err := subtitle.ParseVTT(vttText)
+	require.NoError(t, err)
+	a.Equal("Hello & welcome", segments[0].Text)
+
+	_, err = subtitle.ParseVTT(srtText)
+	a.Error(err)
+	_, err = subtitle.ParseSRT("1\n00:00:01,000 -> 00:00:02,000\nbroken\n")
+	a.ErrorContains(err, "line 1")
+	_, err = subtitle.ParseSRT("one\n00:00:01,000 --> 00:00:02,000\nbroken\n")
+	a.Error(err)
+	_, err = subtitle.ParseSRT("1\n00:00:01,000 --> 00:99:02,000\nbroken\n")
+	a.Error(err)
+}
+
+// TestRender tests that segments are rendered into subtitles that parse
+// back into them.
+func TestRender(t *testing.T) {
+	a := assert.New(t)
+	segments, err := subtitle.ParseSRT(srtText)
+	require.NoError(t, err)
+	segments[0].Text = " Fish & <chips> "
+	a.Equal("1\n"+
+		"00:00:01,000 --> 00:00:02,500\n"+
+		"Fish & <chips>\n"+
+		"\n"+
+		"2\n"+
+		"01:02:03,045 --> 01:02:04,000\n"+
+		"Two\n"+
+		"lines\n", subtitle.SRT(segments))
+	vtt := subtitle.VTT(segments)
+	a.Equal("WEBVTT\n"+
+		"\n"+
+		"00:00:01.000 --> 00:00:02.500\n"+
+		"Fish &amp; &lt;chips&gt;\n"+
+		"\n"+
+		"01:02:03.045 --> 01:02:04.000\n"+
+		"Two\n"+
+		"lines\n", vtt)
+	parsed, err := subtitle.Parse(vtt)
+	require.NoError(t, err)
+	a.Equal("Fish & <chips>", parsed[0].Text)
+
+	rendered, err := subtitle.Render(groq.FormatSRT, segments)
+	require.NoError(t, err)
+	a.Equal(subtitle.SRT(segments), rendered)
+	_, err = subtitle.Render(groq.FormatJSON, segments)
+	a.Error(err)
+}
+
+// words returns words of the text lasting half a second each, with a pause
+// of two seconds before the words starting with an upper case letter.
+func words(text ...string) groq.Words {
+	ws := make(groq.Words, len(text))
+	at := 0.0
+	for i, word := range text {
+		if i > 0 && word[0] >= 'A' && word[0] <= 'Z' {
+			at += 2
+		}
+		ws[i].Word = word
+		ws[i].Start = at
+		ws[i].End = at + 0.5
+		at += 0.5
+	}
+	return ws
+}
+
+// TestCaptions tests that captions are re-segmented from words and wrapped
+// from segments.
+func TestCaptions(t *testing.T) {
+	a := assert.New(t)
+	response := groq.AudioResponse{Words: words(
+		"one", "two.", "three", "four", "five", "six", "seven",
+		"eight", "Nine", "ten",
+	)}
+	captions := subtitle.Captions(response, subtitle.WithMaxLineLength(10))
+	texts := make([]string, len(captions))
+	for i, caption := range captions {
+		a.Equal(i, caption.ID)
+		texts[i] = caption.Text
+	}
+	a.Equal([]string{"one two.", "three four\nfive six", "seven\neight", "Nine ten"}, texts)
+	a.InDelta(1.0, captions[1].Start, 1e-9)
+	a.InDelta(3.0, captions[1].End, 1e-9)
+	a.InDelta(6.0, captions[3].Start, 1e-9)
+
+	captions = subtitle.FromWords(response.Words, subtitle.WithMaxDuration(time.Second))
+	a.Len(captions, 5)
+
+	segments, err := subtitle.ParseSRT("1\n00:00:00,000 --> 00:00:04,000\naaaa bbbb cccc dddd\n")
+	require.NoError(t, err)
+	response = groq.AudioResponse{Segments: segments}
+	captions = subtitle.Captions(response, subtitle.WithMaxLineLength(9), subtitle.WithMaxLines(1))
+	require.Len(t, captions, 2)
+	a.Equal("aaaa bbbb", captions[0].Text)
+	a.InDelta(2.0, captions[0].End, 1e-9)
+	a.Equal("cccc dddd", captions[1].Text)
+	a.InDelta(2.0, captions[1].Start, 1e-9)
+	a.InDelta(4.0, captions[1].End, 1e-9)
+}
+
+// TestShiftScale tests that timestamps are shifted and scaled.
+func TestShiftScale(t *testing.T) {
+	a := assert.New(t)
+	segments, err := subtitle.ParseSRT(srtText)
+	require.NoError(t, err)
+	shifted := subtitle.Shift(segments, -1500*time.Millisecond)
+	require.Len(t, shifted, 2)
+	a.Zero(shifted[0].Start)
+	a.InDelta(1.0, shifted[0].End, 1e-9)
+	a.InDelta(1.0, segments[0].Start, 1e-9, "the segments should be copied")
+	shifted = subtitle.Shift(segments, -3*time.Second)
+	require.Len(t, shifted, 1)
+	a.Equal(0, shifted[0].ID)
+	a.Equal("Two\nlines", shifted[0].Text)
+
+	scaled := subtitle.Scale(segments, 2)
+	a.InDelta(2.0, scaled[0].Start, 1e-9)
+	a.InDelta(5.0, scaled[0].End, 1e-9)
+	a.InDelta(1.0, segments[0].Start, 1e-9)
+}
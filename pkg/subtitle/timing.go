@@ -0,0 +1,39 @@
+package subtitle
+
+import (
+	"slices"
+	"time"
+
+	"github.com/conneroisu/groq-go"
+)
+
+// Shift returns a copy of the segments with their timestamps moved by d,
+// earlier for a negative d. Timestamps moved before zero are clamped to
+// zero, segments moved to end at zero are dropped and the others are
+// numbered again.
+func Shift(segments groq.Segments, d time.Duration) groq.Segments {
+	offset := d.Seconds()
+	shifted := make(groq.Segments, 0, len(segments))
+	for _, segment := range segments {
+		segment.Start = max(0, segment.Start+offset)
+		segment.End = max(0, segment.End+offset)
+		if segment.End <= 0 && offset < 0 {
+			continue
+		}
+		segment.ID = len(shifted)
+		shifted = append(shifted, segment)
+	}
+	return shifted
+}
+
+// Scale returns a copy of the segments with their timestamps multiplied by
+// factor, such as 23.976/25 to follow a video sped up from 23.976 to 25
+// frames per second.
+func Scale(segments groq.Segments, factor float64) groq.Segments {
+	scaled := slices.Clone(segments)
+	for i := range scaled {
+		scaled[i].Start *= factor
+		scaled[i].End *= factor
+	}
+	return scaled
+}
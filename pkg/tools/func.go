@@ -0,0 +1,156 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"github.com/conneroisu/groq-go/pkg/jsonschema"
+)
+
+type (
+	// FuncTool is a tool generated from a typed Go function.
+	FuncTool[Args, Result any] struct {
+		// Tool is the definition of the tool sent to the model.
+		Tool   Tool
+		schema *jsonschema.Schema
+		fn     func(ctx context.Context, args Args) (Result, error)
+	}
+	// Validator can be implemented by the arguments of a FuncTool to
+	// validate them after they have been decoded.
+	Validator interface {
+		Validate() error
+	}
+)
+
+// FromFunc creates a tool from a typed Go function.
+//
+// The parameters of the tool are reflected from the Args struct using its
+// json and jsonschema struct tags, so nested objects, arrays, enums and
+// bounds are described to the model.
+//
+// Args must be a struct or a pointer to a struct.
+func FromFunc[Args, Result any](
+	name, description string,
+	fn func(ctx context.Context, args Args) (Result, error),
+) (*FuncTool[Args, Result], error) {
+	var args Args
+	s, params, err := reflectParameters(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reflect parameters of %s: %w", name, err)
+	}
+	return &FuncTool[Args, Result]{
+		Tool: Tool{
+			Type: ToolTypeFunction,
+			Function: FunctionDefinition{
+				Name:        name,
+				Description: description,
+				Parameters:  params,
+			},
+		},
+		schema: s,
+		fn:     fn,
+	}, nil
+}
+
+// MustFromFunc is like FromFunc but panics if the parameters can not be
+// reflected.
+func MustFromFunc[Args, Result any](
+	name, description string,
+	fn func(ctx context.Context, args Args) (Result, error),
+) *FuncTool[Args, Result] {
+	tool, err := FromFunc(name, description, fn)
+	if err != nil {
+		panic(err)
+	}
+	return tool
+}
+
+// Name returns the name of the tool.
+func (t *FuncTool[Args, Result]) Name() string {
+	return t.Tool.Function.Name
+}
+
+// Run runs a call of the tool.
+//
+// The arguments of the call are validated against the schema of the tool's
+// parameters and decoded into Args before calling the function. The result
+// is returned as is if it is a string and encoded as JSON otherwise.
+func (t *FuncTool[Args, Result]) Run(
+	ctx context.Context,
+	call ToolCall,
+) (string, error) {
+	if call.Function.Name != t.Name() {
+		return "", groqerr.ErrToolNotFound{ToolName: call.Function.Name}
+	}
+	args, err := t.decode(call.Function.Arguments)
+	if err != nil {
+		return "", groqerr.ErrToolArguments{ToolName: t.Name(), Err: err}
+	}
+	result, err := t.fn(ctx, args)
+	if err != nil {
+		return "", err
+	}
+	if s, ok := any(result).(string); ok {
+		return s, nil
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode result of %s: %w", t.Name(), err)
+	}
+	return string(out), nil
+}
+
+// decode decodes and validates the arguments of a call.
+func (t *FuncTool[Args, Result]) decode(arguments string) (args Args, err error) {
+	arguments = strings.TrimSpace(arguments)
+	if arguments == "" {
+		arguments = "{}"
+	}
+	err = t.schema.Validate([]byte(arguments))
+	if err != nil {
+		return args, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(arguments)))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(&args)
+	if err != nil {
+		return args, err
+	}
+	if v, ok := any(args).(Validator); ok {
+		err = v.Validate()
+	} else if v, ok := any(&args).(Validator); ok {
+		err = v.Validate()
+	}
+	return args, err
+}
+
+// reflectParameters reflects the function parameters of a tool from its
+// arguments.
+func reflectParameters(
+	args any,
+) (*jsonschema.Schema, FunctionParameters, error) {
+	var params FunctionParameters
+	s, err := jsonschema.ReflectExpandedSchema(args)
+	if err != nil {
+		return nil, params, err
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, params, err
+	}
+	err = json.Unmarshal(b, &params)
+	if err != nil {
+		return nil, params, err
+	}
+	if params.Properties == nil {
+		params.Properties = map[string]PropertyDefinition{}
+	}
+	if params.Required == nil {
+		params.Required = []string{}
+	}
+	return s, params, nil
+}
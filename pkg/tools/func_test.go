@@ -0,0 +1,151 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"github.com/conneroisu/groq-go/pkg/tools"
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	forecastArgs struct {
+		City     string   `json:"city" jsonschema:"description=The city to forecast."`
+		Unit     string   `json:"unit,omitempty" jsonschema:"enum=celsius,enum=fahrenheit"`
+		Days     int      `json:"days" jsonschema:"minimum=1,maximum=7"`
+		Location *latLong `json:"location,omitempty"`
+		Tags     []string `json:"tags,omitempty"`
+	}
+	latLong struct {
+		Lat  float64 `json:"lat"`
+		Long float64 `json:"long"`
+	}
+	forecast struct {
+		City  string    `json:"city"`
+		Temps []float64 `json:"temps"`
+	}
+)
+
+func (a forecastArgs) Validate() error {
+	if a.City == "nowhere" {
+		return errors.New("unknown city")
+	}
+	return nil
+}
+
+func newForecastTool(t *testing.T) *tools.FuncTool[forecastArgs, forecast] {
+	t.Helper()
+	tool, err := tools.FromFunc(
+		"forecast",
+		"Forecast the weather of a city.",
+		func(_ context.Context, args forecastArgs) (forecast, error) {
+			return forecast{
+				City:  args.City,
+				Temps: make([]float64, args.Days),
+			}, nil
+		},
+	)
+	assert.NoError(t, err)
+	return tool
+}
+
+// TestFromFuncParameters tests that the parameters of a tool are reflected
+// from its arguments.
+func TestFromFuncParameters(t *testing.T) {
+	a := assert.New(t)
+	tool := newForecastTool(t)
+	a.Equal(tools.ToolTypeFunction, tool.Tool.Type)
+	a.Equal("forecast", tool.Name())
+	params := tool.Tool.Function.Parameters
+	a.Equal("object", params.Type)
+	a.ElementsMatch([]string{"city", "days"}, params.Required)
+	a.Equal("The city to forecast.", params.Properties["city"].Description)
+	a.Equal([]any{"celsius", "fahrenheit"}, params.Properties["unit"].Enum)
+	a.Equal(1.0, *params.Properties["days"].Minimum)
+	a.Equal(7.0, *params.Properties["days"].Maximum)
+	a.Equal("array", params.Properties["tags"].Type)
+	a.Equal("string", params.Properties["tags"].Items.Type)
+	a.Equal("#/$defs/latLong", params.Properties["location"].Ref)
+	a.Equal("number", params.Defs["latLong"].Properties["lat"].Type)
+	b, err := json.Marshal(tool.Tool)
+	a.NoError(err)
+	a.Contains(string(b), `"$defs":{"latLong":`)
+	a.NotContains(string(b), `"$schema"`)
+}
+
+// TestPropertyDefinitionJSON tests that a plain property always sends its
+// type and description while a reference omits them when empty.
+func TestPropertyDefinitionJSON(t *testing.T) {
+	a := assert.New(t)
+	b, err := json.Marshal(tools.PropertyDefinition{Type: "string"})
+	a.NoError(err)
+	a.Equal(`{"type":"string","description":""}`, string(b))
+	b, err = json.Marshal(tools.PropertyDefinition{Ref: "#/$defs/latLong"})
+	a.NoError(err)
+	a.Equal(`{"$ref":"#/$defs/latLong"}`, string(b))
+	b, err = json.Marshal(tools.PropertyDefinition{
+		Ref:         "#/$defs/latLong",
+		Description: "Where to forecast.",
+	})
+	a.NoError(err)
+	a.Equal(`{"description":"Where to forecast.","$ref":"#/$defs/latLong"}`, string(b))
+}
+
+// TestFromFuncRun tests the typed dispatch of tool calls.
+func TestFromFuncRun(t *testing.T) {
+	a := assert.New(t)
+	tool := newForecastTool(t)
+	ctx := context.Background()
+	out, err := tool.Run(ctx, tools.ToolCall{
+		Function: tools.FunctionCall{
+			Name:      "forecast",
+			Arguments: `{"city":"Paris","days":2}`,
+		},
+	})
+	a.NoError(err)
+	a.JSONEq(`{"city":"Paris","temps":[0,0]}`, out)
+
+	for _, args := range []string{
+		`{"days":2}`,
+		`{"city":"Paris","days":2,"extra":true}`,
+		`{"city":"nowhere","days":2}`,
+		`not json`,
+	} {
+		_, err = tool.Run(ctx, tools.ToolCall{
+			Function: tools.FunctionCall{Name: "forecast", Arguments: args},
+		})
+		a.ErrorAs(err, &groqerr.ErrToolArguments{}, args)
+	}
+	_, err = tool.Run(ctx, tools.ToolCall{
+		Function: tools.FunctionCall{Name: "other"},
+	})
+	a.ErrorAs(err, &groqerr.ErrToolNotFound{})
+}
+
+// TestFromFuncStringResult tests that string results are returned as is.
+func TestFromFuncStringResult(t *testing.T) {
+	a := assert.New(t)
+	tool := tools.MustFromFunc(
+		"echo",
+		"Echo the text.",
+		func(_ context.Context, args struct {
+			Text string `json:"text"`
+		}) (string, error) {
+			return args.Text, nil
+		},
+	)
+	out, err := tool.Run(context.Background(), tools.ToolCall{
+		Function: tools.FunctionCall{Name: "echo", Arguments: `{"text":"hi"}`},
+	})
+	a.NoError(err)
+	a.Equal("hi", out)
+	_, err = tools.FromFunc(
+		"bad",
+		"Arguments must be a struct.",
+		func(context.Context, int) (string, error) { return "", nil },
+	)
+	a.Error(err)
+}
@@ -1,5 +1,7 @@
 package tools
 
+import "encoding/json"
+
 const (
 	// ToolTypeFunction is the function tool type.
 	ToolTypeFunction ToolType = "function"
@@ -38,14 +40,55 @@ type (
 	// FunctionParameters represents the function parameters of a tool.
 	FunctionParameters struct {
 		Type                 string                        `json:"type"`
+		Description          string                        `json:"description,omitempty"`
 		Properties           map[string]PropertyDefinition `json:"properties"`
 		Required             []string                      `json:"required"`
 		AdditionalProperties bool                          `json:"additionalProperties,omitempty"`
+		// Defs are the definitions referenced by the properties with
+		// "#/$defs/<name>" refs.
+		Defs map[string]PropertyDefinition `json:"$defs,omitempty"`
 	}
 	// PropertyDefinition represents the property definition.
+	//
+	// Besides a flat type and description, it can describe nested objects,
+	// arrays, enums and references to the definitions of the parameters
+	// using the matching JSON schema keywords.
 	PropertyDefinition struct {
 		Type        string `json:"type"`
 		Description string `json:"description"`
+		// Ref references a definition of the parameters.
+		//
+		// A property with a Ref omits its type and description when they
+		// are empty, as an empty type is not a valid JSON schema type.
+		Ref     string `json:"$ref,omitempty"`
+		Enum    []any  `json:"enum,omitempty"`
+		Const   any    `json:"const,omitempty"`
+		Default any    `json:"default,omitempty"`
+		Format  string `json:"format,omitempty"`
+		Pattern string `json:"pattern,omitempty"`
+		// Minimum, Maximum, ExclusiveMinimum and ExclusiveMaximum bound
+		// numeric values.
+		Minimum          *float64 `json:"minimum,omitempty"`
+		Maximum          *float64 `json:"maximum,omitempty"`
+		ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+		ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+		MinLength        *uint64  `json:"minLength,omitempty"`
+		MaxLength        *uint64  `json:"maxLength,omitempty"`
+		// Items is the definition of the elements of an array.
+		Items       *PropertyDefinition `json:"items,omitempty"`
+		MinItems    *uint64             `json:"minItems,omitempty"`
+		MaxItems    *uint64             `json:"maxItems,omitempty"`
+		UniqueItems bool                `json:"uniqueItems,omitempty"`
+		// Properties and Required describe the fields of an object.
+		Properties map[string]PropertyDefinition `json:"properties,omitempty"`
+		Required   []string                      `json:"required,omitempty"`
+		// AdditionalProperties is either a bool or the PropertyDefinition
+		// of the values of a map.
+		AdditionalProperties any                           `json:"additionalProperties,omitempty"`
+		PatternProperties    map[string]PropertyDefinition `json:"patternProperties,omitempty"`
+		AnyOf                []PropertyDefinition          `json:"anyOf,omitempty"`
+		OneOf                []PropertyDefinition          `json:"oneOf,omitempty"`
+		AllOf                []PropertyDefinition          `json:"allOf,omitempty"`
 	}
 	// ToolCall represents a tool call.
 	ToolCall struct {
@@ -66,3 +109,16 @@ type (
 		Arguments string `json:"arguments,omitempty"`
 	}
 )
+
+// MarshalJSON implements the json.Marshaler interface.
+func (p PropertyDefinition) MarshalJSON() ([]byte, error) {
+	type property PropertyDefinition
+	if p.Ref == "" {
+		return json.Marshal(property(p))
+	}
+	return json.Marshal(struct {
+		Type        string `json:"type,omitempty"`
+		Description string `json:"description,omitempty"`
+		property
+	}{p.Type, p.Description, property(p)})
+}
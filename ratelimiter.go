@@ -0,0 +1,292 @@
+package groq
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+)
+
+const (
+	// RateLimitBlock makes the rate limiter wait until the request fits
+	// within the limits.
+	RateLimitBlock RateLimitMode = iota
+	// RateLimitFailFast makes the rate limiter return a
+	// groqerr.ErrRateLimited immediately when the request does not fit
+	// within the limits.
+	RateLimitFailFast
+)
+
+type (
+	// RateLimitMode determines what the rate limiter does with requests
+	// exceeding the limits.
+	RateLimitMode int
+	// ModelLimits are the per minute limits of a model.
+	//
+	// A zero value disables the corresponding limit.
+	ModelLimits struct {
+		// RequestsPerMinute is the number of requests allowed per
+		// minute.
+		RequestsPerMinute int
+		// TokensPerMinute is the number of tokens allowed per minute.
+		TokensPerMinute int
+	}
+	// RateLimiterConfig configures a RateLimiter.
+	RateLimiterConfig struct {
+		// Default are the limits applied to models missing from Models.
+		Default ModelLimits
+		// Models are the limits of specific models keyed by model id.
+		Models map[string]ModelLimits
+		// Mode determines whether requests wait for capacity or fail
+		// fast.
+		Mode RateLimitMode
+	}
+	// RateLimiter is a client side rate limiter keeping requests and
+	// tokens per minute buckets for each model.
+	//
+	// It is safe for concurrent use and is meant to be shared by every
+	// goroutine using the same api key.
+	RateLimiter struct {
+		mu      sync.Mutex
+		config  RateLimiterConfig
+		buckets map[string]*modelBuckets
+		now     func() time.Time
+	}
+	// modelBuckets are the buckets of a single model.
+	modelBuckets struct {
+		requests *tokenBucket
+		tokens   *tokenBucket
+	}
+	// tokenBucket is a continuously refilled token bucket.
+	tokenBucket struct {
+		capacity  float64
+		available float64
+		// baseRate is the refill rate per second derived from the
+		// configured limit.
+		baseRate float64
+		// rate is the current refill rate per second which is adjusted
+		// to the reset hints of the server until they pass.
+		rate float64
+		// until is the time the server resets the bucket, after which
+		// it refills at the base rate again.
+		until time.Time
+		last  time.Time
+	}
+)
+
+// NewRateLimiter creates a new rate limiter with the given configuration.
+func NewRateLimiter(config RateLimiterConfig) *RateLimiter {
+	return &RateLimiter{
+		config:  config,
+		buckets: make(map[string]*modelBuckets),
+		now:     time.Now,
+	}
+}
+
+// WithRateLimiter sets the client side rate limiter for the Groq client.
+//
+// The same limiter can be shared by multiple clients using the same api key.
+func WithRateLimiter(limiter *RateLimiter) Opts {
+	return func(c *Client) { c.rateLimiter = limiter }
+}
+
+// Wait reserves a request and the given number of tokens for the model.
+//
+// Depending on the configured mode it either blocks until the reservation
+// fits within the limits or returns a groqerr.ErrRateLimited.
+func (l *RateLimiter) Wait(ctx context.Context, model string, tokens int) error {
+	for {
+		wait, err := l.reserve(model, tokens)
+		if err != nil || wait == 0 {
+			return err
+		}
+		if err = sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve takes the request and tokens from the buckets of the model if
+// they are available and otherwise returns the time to wait for them.
+func (l *RateLimiter) reserve(model string, tokens int) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	b := l.bucketsFor(model, now)
+	needTokens := float64(tokens)
+	if b.tokens != nil {
+		// a reservation larger than the bucket could never be satisfied
+		needTokens = math.Min(needTokens, b.tokens.capacity)
+	}
+	wait := max(b.requests.waitFor(1, now), b.tokens.waitFor(needTokens, now))
+	if wait > 0 {
+		if l.config.Mode == RateLimitFailFast {
+			return 0, groqerr.ErrRateLimited{Model: model, RetryAfter: wait}
+		}
+		return wait, nil
+	}
+	b.requests.take(1)
+	b.tokens.take(needTokens)
+	return 0, nil
+}
+
+// Observe updates the buckets of the model after a request completed.
+//
+// The estimated tokens are corrected with the actual usage when it is
+// known and the tokens bucket is resynchronized with the per minute token
+// headers. The request headers of groq count requests per day, so they are
+// not synced into the per minute requests bucket.
+func (l *RateLimiter) Observe(
+	model string,
+	estimated int,
+	usage *Usage,
+	limits RateLimits,
+) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	b := l.bucketsFor(model, now)
+	if usage != nil && b.tokens != nil {
+		b.tokens.refill(now)
+		b.tokens.take(float64(usage.TotalTokens - estimated))
+	}
+	if limits.LimitTokens > 0 {
+		b.tokens.sync(limits.RemainingTokens, limits.ResetTokens, now)
+	}
+}
+
+// bucketsFor returns the buckets of the model, creating them on first use.
+//
+// It must be called with the lock held.
+func (l *RateLimiter) bucketsFor(model string, now time.Time) *modelBuckets {
+	b, ok := l.buckets[model]
+	if ok {
+		return b
+	}
+	limits, ok := l.config.Models[model]
+	if !ok {
+		limits = l.config.Default
+	}
+	b = &modelBuckets{
+		requests: newTokenBucket(limits.RequestsPerMinute, now),
+		tokens:   newTokenBucket(limits.TokensPerMinute, now),
+	}
+	l.buckets[model] = b
+	return b
+}
+
+// newTokenBucket creates a full bucket for the given per minute limit or
+// nil when the limit is disabled.
+func newTokenBucket(perMinute int, now time.Time) *tokenBucket {
+	if perMinute <= 0 {
+		return nil
+	}
+	rate := float64(perMinute) / time.Minute.Seconds()
+	return &tokenBucket{
+		capacity:  float64(perMinute),
+		available: float64(perMinute),
+		baseRate:  rate,
+		rate:      rate,
+		last:      now,
+	}
+}
+
+// refill adds the tokens accumulated since the last refill, going back to
+// the base rate once the reset of the server has passed or the bucket is
+// full.
+func (b *tokenBucket) refill(now time.Time) {
+	if !now.After(b.last) {
+		return
+	}
+	if b.rate != b.baseRate && !now.Before(b.until) {
+		b.available += b.until.Sub(b.last).Seconds() * b.rate
+		b.last = b.until
+		b.rate = b.baseRate
+	}
+	b.available = math.Min(
+		b.capacity,
+		b.available+now.Sub(b.last).Seconds()*b.rate,
+	)
+	b.last = now
+	if b.available >= b.capacity {
+		b.rate = b.baseRate
+	}
+}
+
+// waitFor returns the time until n tokens are available.
+func (b *tokenBucket) waitFor(n float64, now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+	b.refill(now)
+	if b.available >= n {
+		return 0
+	}
+	missing := n - b.available
+	var wait time.Duration
+	if b.rate != b.baseRate {
+		untilReset := b.until.Sub(now)
+		if missing > untilReset.Seconds()*b.rate {
+			missing -= untilReset.Seconds() * b.rate
+			wait = untilReset
+		} else {
+			return max(seconds(missing/b.rate), time.Millisecond)
+		}
+	}
+	return max(wait+seconds(missing/b.baseRate), time.Millisecond)
+}
+
+// seconds converts a number of seconds to a duration.
+func seconds(secs float64) time.Duration {
+	return time.Duration(secs * float64(time.Second))
+}
+
+// take removes n tokens from the bucket.
+//
+// A negative n gives tokens back to the bucket.
+func (b *tokenBucket) take(n float64) {
+	if b == nil {
+		return
+	}
+	b.available = math.Min(b.capacity, b.available-n)
+}
+
+// sync lowers the available tokens to the remaining tokens reported by the
+// server and refills the bucket by the time the server resets it, after
+// which the bucket refills at its base rate again.
+func (b *tokenBucket) sync(remaining int, reset time.Duration, now time.Time) {
+	if b == nil {
+		return
+	}
+	b.refill(now)
+	b.available = math.Min(b.available, float64(remaining))
+	if reset > 0 && b.available < b.capacity {
+		b.rate = (b.capacity - b.available) / reset.Seconds()
+		b.until = now.Add(reset)
+	}
+}
+
+// acquire reserves capacity for a request on the client's rate limiter if
+// one is configured.
+func (c *Client) acquire(ctx context.Context, model string, tokens int) error {
+	if c.rateLimiter == nil {
+		return nil
+	}
+	return c.rateLimiter.Wait(ctx, model, tokens)
+}
+
+// observe reports a completed request to the client's rate limiter if one
+// is configured.
+func (c *Client) observe(
+	model string,
+	estimated int,
+	usage *Usage,
+	limits RateLimits,
+) {
+	if c.rateLimiter == nil {
+		return
+	}
+	c.rateLimiter.Observe(model, estimated, usage, limits)
+}
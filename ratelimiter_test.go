@@ -0,0 +1,204 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/conneroisu/groq-go/internal/test"
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock is a manually advanced clock for the rate limiter.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestRateLimiter(config RateLimiterConfig) (*RateLimiter, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	limiter := NewRateLimiter(config)
+	limiter.now = clock.Now
+	return limiter, clock
+}
+
+func TestRateLimiterFailFast(t *testing.T) {
+	a := assert.New(t)
+	limiter, clock := newTestRateLimiter(RateLimiterConfig{
+		Default: ModelLimits{RequestsPerMinute: 2},
+		Mode:    RateLimitFailFast,
+	})
+	ctx := context.Background()
+	a.NoError(limiter.Wait(ctx, "model", 0))
+	a.NoError(limiter.Wait(ctx, "model", 0))
+	err := limiter.Wait(ctx, "model", 0)
+	var rateErr groqerr.ErrRateLimited
+	a.True(errors.As(err, &rateErr))
+	a.Equal("model", rateErr.Model)
+	a.Equal(30*time.Second, rateErr.RetryAfter)
+	// other models have their own buckets
+	a.NoError(limiter.Wait(ctx, "other", 0))
+	clock.Advance(30 * time.Second)
+	a.NoError(limiter.Wait(ctx, "model", 0))
+}
+
+func TestRateLimiterTokens(t *testing.T) {
+	a := assert.New(t)
+	limiter, clock := newTestRateLimiter(RateLimiterConfig{
+		Models: map[string]ModelLimits{
+			"model": {TokensPerMinute: 600},
+		},
+		Mode: RateLimitFailFast,
+	})
+	ctx := context.Background()
+	a.NoError(limiter.Wait(ctx, "model", 500))
+	a.Error(limiter.Wait(ctx, "model", 200))
+	// the actual usage was lower than the estimate
+	limiter.Observe("model", 500, &Usage{TotalTokens: 300}, RateLimits{})
+	a.NoError(limiter.Wait(ctx, "model", 200))
+	clock.Advance(time.Minute)
+	// requests larger than the bucket drain it instead of failing forever
+	a.NoError(limiter.Wait(ctx, "model", 10000))
+}
+
+func TestRateLimiterSync(t *testing.T) {
+	a := assert.New(t)
+	limiter, clock := newTestRateLimiter(RateLimiterConfig{
+		Default: ModelLimits{TokensPerMinute: 6000},
+		Mode:    RateLimitFailFast,
+	})
+	ctx := context.Background()
+	a.NoError(limiter.Wait(ctx, "model", 10))
+	limiter.Observe("model", 10, nil, RateLimits{
+		LimitTokens:     6000,
+		RemainingTokens: 0,
+		ResetTokens:     10 * time.Second,
+	})
+	err := limiter.Wait(ctx, "model", 1000)
+	var rateErr groqerr.ErrRateLimited
+	a.True(errors.As(err, &rateErr))
+	a.InDelta(float64(10*time.Second/6), float64(rateErr.RetryAfter), float64(time.Millisecond))
+	clock.Advance(10 * time.Second)
+	a.NoError(limiter.Wait(ctx, "model", 6000))
+}
+
+func TestRateLimiterIgnoresDailyRequests(t *testing.T) {
+	a := assert.New(t)
+	limiter, clock := newTestRateLimiter(RateLimiterConfig{
+		Default: ModelLimits{RequestsPerMinute: 30},
+		Mode:    RateLimitFailFast,
+	})
+	ctx := context.Background()
+	a.NoError(limiter.Wait(ctx, "model", 0))
+	// the request headers are per day and must not slow the per minute
+	// bucket down to the daily reset
+	limiter.Observe("model", 0, nil, RateLimits{
+		LimitRequests:     14400,
+		RemainingRequests: 14399,
+		ResetRequests:     2*time.Minute + 59560*time.Millisecond,
+	})
+	for range 29 {
+		a.NoError(limiter.Wait(ctx, "model", 0))
+	}
+	a.Error(limiter.Wait(ctx, "model", 0))
+	clock.Advance(2 * time.Second)
+	a.NoError(limiter.Wait(ctx, "model", 0))
+}
+
+func TestRateLimiterSyncRestoresRate(t *testing.T) {
+	a := assert.New(t)
+	limiter, clock := newTestRateLimiter(RateLimiterConfig{
+		Default: ModelLimits{TokensPerMinute: 600},
+		Mode:    RateLimitFailFast,
+	})
+	ctx := context.Background()
+	a.NoError(limiter.Wait(ctx, "model", 100))
+	// the server limit and reset differ from the configured limit
+	limiter.Observe("model", 100, nil, RateLimits{
+		LimitTokens:     6000,
+		RemainingTokens: 0,
+		ResetTokens:     30 * time.Second,
+	})
+	err := limiter.Wait(ctx, "model", 300)
+	var rateErr groqerr.ErrRateLimited
+	a.True(errors.As(err, &rateErr))
+	a.InDelta(float64(15*time.Second), float64(rateErr.RetryAfter), float64(time.Millisecond))
+	clock.Advance(15 * time.Second)
+	a.NoError(limiter.Wait(ctx, "model", 300))
+	// the bucket refills at the configured rate once the reset passed
+	// even though it never filled up
+	clock.Advance(20 * time.Second)
+	a.NoError(limiter.Wait(ctx, "model", 350))
+	err = limiter.Wait(ctx, "model", 100)
+	a.True(errors.As(err, &rateErr))
+	a.InDelta(float64(10*time.Second), float64(rateErr.RetryAfter), float64(time.Millisecond))
+}
+
+func TestRateLimiterBlockRespectsContext(t *testing.T) {
+	a := assert.New(t)
+	limiter := NewRateLimiter(RateLimiterConfig{
+		Default: ModelLimits{RequestsPerMinute: 1},
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	a.NoError(limiter.Wait(ctx, "model", 0))
+	a.ErrorIs(limiter.Wait(ctx, "model", 0), context.DeadlineExceeded)
+}
+
+func TestRateLimiterClient(t *testing.T) {
+	a := assert.New(t)
+	ts := test.NewTestServer()
+	ts.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"hi"}}],"usage":{"total_tokens":5}}`))
+	})
+	testS := ts.GroqTestServer()
+	testS.Start()
+	defer testS.Close()
+	limiter := NewRateLimiter(RateLimiterConfig{
+		Default: ModelLimits{RequestsPerMinute: 1},
+		Mode:    RateLimitFailFast,
+	})
+	client, err := NewClient(
+		test.GetTestToken(),
+		WithBaseURL(testS.URL+"/v1"),
+		WithRateLimiter(limiter),
+	)
+	a.NoError(err)
+	request := ChatCompletionRequest{
+		Model: ModelLlama38B8192,
+		Messages: []ChatCompletionMessage{
+			{Role: RoleUser, Content: "Hello!"},
+		},
+	}
+	_, err = client.ChatCompletion(context.Background(), request)
+	a.NoError(err)
+	_, err = client.ChatCompletion(context.Background(), request)
+	a.ErrorAs(err, &groqerr.ErrRateLimited{})
+}
+
+func TestEstimateTokens(t *testing.T) {
+	a := assert.New(t)
+	a.Equal(tokensPerPrompt, EstimateTokens(nil))
+	a.Equal(
+		tokensPerPrompt+tokensPerMessage+2,
+		EstimateTokens([]ChatCompletionMessage{
+			{Role: RoleUser, Content: "Hello!"},
+		}),
+	)
+}
@@ -0,0 +1,119 @@
+package groq
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// RateLimits are the rate limit values reported by the Groq API in
+	// the headers of a response.
+	//
+	// Values that are missing from the headers are left as their zero
+	// value.
+	RateLimits struct {
+		// LimitRequests is the maximum number of requests allowed in
+		// the current window (x-ratelimit-limit-requests).
+		LimitRequests int
+		// LimitTokens is the maximum number of tokens allowed in the
+		// current window (x-ratelimit-limit-tokens).
+		LimitTokens int
+		// RemainingRequests is the number of requests left in the
+		// current window (x-ratelimit-remaining-requests).
+		RemainingRequests int
+		// RemainingTokens is the number of tokens left in the current
+		// window (x-ratelimit-remaining-tokens).
+		RemainingTokens int
+		// ResetRequests is the time until the request limit resets
+		// (x-ratelimit-reset-requests).
+		ResetRequests time.Duration
+		// ResetTokens is the time until the token limit resets
+		// (x-ratelimit-reset-tokens).
+		ResetTokens time.Duration
+		// RetryAfter is the time to wait before retrying a rate limited
+		// request (retry-after).
+		RetryAfter time.Duration
+	}
+)
+
+// ParseRateLimits parses the rate limit headers of a Groq API response.
+func ParseRateLimits(header http.Header) RateLimits {
+	var limits RateLimits
+	if header == nil {
+		return limits
+	}
+	limits.LimitRequests = parseHeaderInt(header, "x-ratelimit-limit-requests")
+	limits.LimitTokens = parseHeaderInt(header, "x-ratelimit-limit-tokens")
+	limits.RemainingRequests = parseHeaderInt(
+		header,
+		"x-ratelimit-remaining-requests",
+	)
+	limits.RemainingTokens = parseHeaderInt(
+		header,
+		"x-ratelimit-remaining-tokens",
+	)
+	limits.ResetRequests, _ = parseResetDuration(
+		header.Get("x-ratelimit-reset-requests"),
+	)
+	limits.ResetTokens, _ = parseResetDuration(
+		header.Get("x-ratelimit-reset-tokens"),
+	)
+	limits.RetryAfter, _ = parseRetryAfter(header.Get("retry-after"))
+	return limits
+}
+
+// RateLimits returns the rate limits reported with the response.
+func (r *ChatCompletionResponse) RateLimits() RateLimits {
+	return ParseRateLimits(r.header)
+}
+
+// RateLimits returns the rate limits reported with the response.
+func (r *AudioResponse) RateLimits() RateLimits {
+	return ParseRateLimits(r.header)
+}
+
+// RateLimits returns the rate limits reported with the response.
+func (r *EmbeddingResponse) RateLimits() RateLimits {
+	return ParseRateLimits(r.header)
+}
+
+// RateLimits returns the rate limits reported with the response.
+func (r *SpeechResponse) RateLimits() RateLimits {
+	return ParseRateLimits(r.header)
+}
+
+// RateLimits returns the rate limits reported with the response.
+func (r *ModerationResponse) RateLimits() RateLimits {
+	return ParseRateLimits(r.header)
+}
+
+// RateLimits returns the rate limits reported with the response.
+func (r *ModelList) RateLimits() RateLimits {
+	return ParseRateLimits(r.header)
+}
+
+// RateLimits returns the rate limits reported with the response.
+func (r *ModelDetails) RateLimits() RateLimits {
+	return ParseRateLimits(r.header)
+}
+
+// RateLimits returns the rate limits reported with the stream's initial
+// response.
+func (s *ChatCompletionStream) RateLimits() RateLimits {
+	if s.StreamReader == nil {
+		return RateLimits{}
+	}
+	return ParseRateLimits(s.Header)
+}
+
+// parseHeaderInt parses an integer header value, returning zero when it is
+// missing or malformed.
+func parseHeaderInt(header http.Header, key string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(header.Get(key)))
+	if err != nil {
+		return 0
+	}
+	return n
+}
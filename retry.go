@@ -0,0 +1,269 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+type (
+	// RetryPolicy configures how the client retries failed requests.
+	//
+	// It applies to every call made by the client including the initial
+	// handshake of a stream.
+	RetryPolicy struct {
+		// MaxAttempts is the maximum number of attempts made for a
+		// request, including the first one.
+		//
+		// A value of one or less disables retries.
+		MaxAttempts int
+		// InitialBackoff is the delay before the first retry.
+		InitialBackoff time.Duration
+		// MaxBackoff is the upper bound of the delay between two
+		// attempts.
+		//
+		// If a server reset hint is longer than MaxBackoff the request is
+		// not retried.
+		MaxBackoff time.Duration
+		// Multiplier is the factor the backoff grows by after each
+		// attempt.
+		Multiplier float64
+		// Jitter is the fraction, between 0 and 1, of the backoff that
+		// is randomized.
+		Jitter float64
+		// RespectResetHints determines whether the Retry-After and
+		// x-ratelimit-reset-* headers of a response are used as the
+		// delay before the next attempt.
+		RespectResetHints bool
+		// RetryableStatusCodes are the http status codes that are
+		// retried.
+		RetryableStatusCodes []int
+		// RetryableError reports whether a transport error is retried.
+		//
+		// Defaults to retrying network errors when nil.
+		RetryableError func(error) bool
+	}
+)
+
+// DefaultRetryPolicy returns the retry policy used by a client when none is
+// configured.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:       3,
+		InitialBackoff:    200 * time.Millisecond,
+		MaxBackoff:        30 * time.Second,
+		Multiplier:        2,
+		Jitter:            0.2,
+		RespectResetHints: true,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryableError: isRetryableError,
+	}
+}
+
+// NoRetryPolicy returns a retry policy that never retries.
+func NoRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+// WithRetryPolicy sets the retry policy for the Groq client.
+func WithRetryPolicy(policy RetryPolicy) Opts {
+	return func(c *Client) { c.retryPolicy = policy }
+}
+
+// do sends the request, retrying it according to the given policy.
+//
+// The response of the last attempt is returned as is so that its error
+// body can be decoded by the caller.
+func (c *Client) do(
+	req *http.Request,
+	policy RetryPolicy,
+) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		res, err := c.client.Do(req)
+		if attempt >= policy.MaxAttempts || ctx.Err() != nil {
+			return res, err
+		}
+		if !policy.shouldRetry(res, err) {
+			return res, err
+		}
+		if req.Body != nil && req.GetBody == nil {
+			return res, err
+		}
+		delay, ok := policy.delay(attempt, res)
+		if !ok {
+			return res, err
+		}
+		if res != nil {
+			_, _ = io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+		c.logger.Debug(
+			"retrying request",
+			"url", req.URL.String(),
+			"attempt", attempt,
+			"delay", delay,
+		)
+		if err = sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+		if req.GetBody != nil {
+			req.Body, err = req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// shouldRetry reports whether the result of an attempt should be retried.
+func (p RetryPolicy) shouldRetry(res *http.Response, err error) bool {
+	if err != nil {
+		retryable := p.RetryableError
+		if retryable == nil {
+			retryable = isRetryableError
+		}
+		return retryable(err)
+	}
+	for _, code := range p.RetryableStatusCodes {
+		if res.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns the delay before the next attempt and whether the request
+// should be attempted again at all.
+func (p RetryPolicy) delay(attempt int, res *http.Response) (time.Duration, bool) {
+	if p.RespectResetHints && res != nil {
+		if hint, ok := resetHint(res); ok {
+			if p.MaxBackoff > 0 && hint > p.MaxBackoff {
+				return 0, false
+			}
+			return hint, true
+		}
+	}
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	backoff := float64(p.InitialBackoff) *
+		math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		backoff -= backoff * jitter * rand.Float64()
+	}
+	return time.Duration(backoff), true
+}
+
+// resetHint returns the delay the server asked for before the next
+// request.
+//
+// The Retry-After header takes precedence over the x-ratelimit-reset-*
+// headers which are only consulted for rate limited responses.
+func resetHint(res *http.Response) (time.Duration, bool) {
+	if after, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+		return after, true
+	}
+	if res.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	var (
+		hint  time.Duration
+		found bool
+	)
+	for _, kind := range []string{"requests", "tokens"} {
+		reset, ok := parseResetDuration(
+			res.Header.Get("x-ratelimit-reset-" + kind),
+		)
+		if !ok {
+			continue
+		}
+		// prefer the reset of the exhausted limit when it is known
+		if res.Header.Get("x-ratelimit-remaining-"+kind) == "0" {
+			return reset, true
+		}
+		hint, found = max(hint, reset), true
+	}
+	return hint, found
+}
+
+// parseResetDuration parses a rate limit reset header value such as "7.66s",
+// "2m59.56s" or "1.5".
+func parseResetDuration(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return d, true
+	}
+	if secs, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(secs * float64(time.Second)), true
+	}
+	return 0, false
+}
+
+// parseRetryAfter parses a Retry-After header value given either in
+// seconds or as an http date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(secs * float64(time.Second)), true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
+
+// isRetryableError reports whether a transport error is transient.
+func isRetryableError(err error) bool {
+	if errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// sleep waits for the given duration or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,204 @@
+package groq_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupRetryTestServer creates a test server whose chat completion endpoint
+// fails with the given status until it has been called failures times.
+func setupRetryTestServer(
+	t *testing.T,
+	status int,
+	failures int32,
+	header http.Header,
+	policy groq.RetryPolicy,
+) (*groq.Client, *atomic.Int32, func()) {
+	t.Helper()
+	calls := new(atomic.Int32)
+	client, server, teardown := setupGroqTestServer(groq.WithRetryPolicy(policy))
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		func(w http.ResponseWriter, r *http.Request) {
+			var req groq.ChatCompletionRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "empty body on retry", http.StatusBadRequest)
+				return
+			}
+			if calls.Add(1) <= failures {
+				for key, values := range header {
+					w.Header()[key] = values
+				}
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte(`{"error":{"message":"try again","type":"server_error"}}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(groq.ChatCompletionResponse{
+				ID: "chatcmpl-retry",
+				Choices: []groq.ChatCompletionChoice{{
+					Message: groq.ChatCompletionMessage{
+						Role:    groq.RoleAssistant,
+						Content: "ok",
+					},
+				}},
+			})
+		},
+	)
+	return client, calls, teardown
+}
+
+func retryTestRequest() groq.ChatCompletionRequest {
+	return groq.ChatCompletionRequest{
+		Model: groq.ModelLlama38B8192,
+		Messages: []groq.ChatCompletionMessage{
+			{Role: groq.RoleUser, Content: "Hello!"},
+		},
+	}
+}
+
+func fastRetryPolicy(attempts int) groq.RetryPolicy {
+	policy := groq.DefaultRetryPolicy()
+	policy.MaxAttempts = attempts
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = time.Second
+	return policy
+}
+
+// TestRetryPolicyRetriesServerErrors tests that 5xx responses are retried
+// until the request succeeds.
+func TestRetryPolicyRetriesServerErrors(t *testing.T) {
+	a := assert.New(t)
+	client, calls, teardown := setupRetryTestServer(
+		t, http.StatusServiceUnavailable, 2, nil, fastRetryPolicy(3),
+	)
+	defer teardown()
+	resp, err := client.ChatCompletion(context.Background(), retryTestRequest())
+	a.NoError(err)
+	a.Equal("ok", resp.Choices[0].Message.Content)
+	a.EqualValues(3, calls.Load())
+}
+
+// TestRetryPolicyMaxAttempts tests that retries stop after MaxAttempts and
+// the last error is returned.
+func TestRetryPolicyMaxAttempts(t *testing.T) {
+	a := assert.New(t)
+	client, calls, teardown := setupRetryTestServer(
+		t, http.StatusInternalServerError, 10, nil, fastRetryPolicy(2),
+	)
+	defer teardown()
+	_, err := client.ChatCompletion(context.Background(), retryTestRequest())
+	var apiErr *groqerr.APIError
+	a.True(errors.As(err, &apiErr))
+	a.Equal(http.StatusInternalServerError, apiErr.HTTPStatusCode)
+	a.EqualValues(2, calls.Load())
+}
+
+// TestRetryPolicyNonRetryableStatus tests that client errors are not
+// retried.
+func TestRetryPolicyNonRetryableStatus(t *testing.T) {
+	a := assert.New(t)
+	client, calls, teardown := setupRetryTestServer(
+		t, http.StatusBadRequest, 10, nil, fastRetryPolicy(5),
+	)
+	defer teardown()
+	_, err := client.ChatCompletion(context.Background(), retryTestRequest())
+	a.Error(err)
+	a.EqualValues(1, calls.Load())
+}
+
+// TestRetryPolicyRetryAfter tests that the Retry-After header is honored.
+func TestRetryPolicyRetryAfter(t *testing.T) {
+	a := assert.New(t)
+	policy := fastRetryPolicy(2)
+	client, calls, teardown := setupRetryTestServer(
+		t,
+		http.StatusTooManyRequests,
+		1,
+		http.Header{"Retry-After": []string{"0.2"}},
+		policy,
+	)
+	defer teardown()
+	start := time.Now()
+	_, err := client.ChatCompletion(context.Background(), retryTestRequest())
+	a.NoError(err)
+	a.EqualValues(2, calls.Load())
+	a.GreaterOrEqual(time.Since(start), 200*time.Millisecond)
+}
+
+// TestRetryPolicyRateLimitReset tests that the x-ratelimit-reset headers
+// are honored and that hints beyond MaxBackoff stop the retries.
+func TestRetryPolicyRateLimitReset(t *testing.T) {
+	a := assert.New(t)
+	policy := fastRetryPolicy(3)
+	policy.MaxBackoff = 50 * time.Millisecond
+	client, calls, teardown := setupRetryTestServer(
+		t,
+		http.StatusTooManyRequests,
+		1,
+		http.Header{
+			"X-Ratelimit-Remaining-Tokens": []string{"0"},
+			"X-Ratelimit-Reset-Tokens":     []string{"2m59.56s"},
+		},
+		policy,
+	)
+	defer teardown()
+	_, err := client.ChatCompletion(context.Background(), retryTestRequest())
+	a.Error(err)
+	a.EqualValues(1, calls.Load())
+}
+
+// TestRetryPolicyContextCanceled tests that the backoff is interrupted when
+// the context is canceled.
+func TestRetryPolicyContextCanceled(t *testing.T) {
+	a := assert.New(t)
+	policy := fastRetryPolicy(5)
+	policy.InitialBackoff = time.Minute
+	policy.MaxBackoff = time.Hour
+	client, calls, teardown := setupRetryTestServer(
+		t, http.StatusServiceUnavailable, 10, nil, policy,
+	)
+	defer teardown()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.ChatCompletion(ctx, retryTestRequest())
+	a.ErrorIs(err, context.DeadlineExceeded)
+	a.EqualValues(1, calls.Load())
+}
+
+// TestNoRetryPolicy tests that NoRetryPolicy disables retries.
+func TestNoRetryPolicy(t *testing.T) {
+	a := assert.New(t)
+	client, calls, teardown := setupRetryTestServer(
+		t, http.StatusServiceUnavailable, 10, nil, groq.NoRetryPolicy(),
+	)
+	defer teardown()
+	_, err := client.ChatCompletion(context.Background(), retryTestRequest())
+	a.Error(err)
+	a.EqualValues(1, calls.Load())
+}
+
+// TestRetryPolicyStreamHandshake tests that the initial handshake of a
+// stream is retried.
+func TestRetryPolicyStreamHandshake(t *testing.T) {
+	a := assert.New(t)
+	client, calls, teardown := setupRetryTestServer(
+		t, http.StatusBadGateway, 1, nil, fastRetryPolicy(2),
+	)
+	defer teardown()
+	stream, err := client.ChatCompletionStream(
+		context.Background(),
+		retryTestRequest(),
+	)
+	a.NoError(err)
+	defer stream.Close()
+	a.EqualValues(2, calls.Load())
+}
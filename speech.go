@@ -0,0 +1,138 @@
+package groq
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/conneroisu/groq-go/pkg/builders"
+)
+
+// speechExtensions are the speech formats of the extensions of audio files.
+var speechExtensions = map[string]SpeechFormat{
+	".wav":  SpeechFormatWAV,
+	".mp3":  SpeechFormatMP3,
+	".flac": SpeechFormatFLAC,
+	".ogg":  SpeechFormatOgg,
+	".ulaw": SpeechFormatMulaw,
+}
+
+// Speech calls the speech endpoint to synthesize the input of the request
+// with a text to speech model.
+//
+// The response streams the audio as it is synthesized and must be closed.
+func (c *Client) Speech(
+	ctx context.Context,
+	request SpeechRequest,
+) (SpeechResponse, error) {
+	return invoke(
+		ctx,
+		c,
+		"Speech",
+		string(request.Model),
+		request,
+		c.speech,
+	)
+}
+
+// speech sends a speech request.
+func (c *Client) speech(
+	ctx context.Context,
+	request SpeechRequest,
+) (SpeechResponse, error) {
+	err := request.validate()
+	if err != nil {
+		return SpeechResponse{}, err
+	}
+	err = c.acquire(ctx, string(request.Model), 0)
+	if err != nil {
+		return SpeechResponse{}, err
+	}
+	req, err := builders.NewRequest(
+		ctx,
+		c.header,
+		http.MethodPost,
+		c.fullURL(speechSuffix, withModel(request.Model)),
+		builders.WithBody(request),
+		builders.WithContentType("application/json"),
+	)
+	if err != nil {
+		return SpeechResponse{}, err
+	}
+	res, err := c.do(req, c.retryPolicy)
+	if err != nil {
+		return SpeechResponse{}, err
+	}
+	response := SpeechResponse{
+		ReadCloser: res.Body,
+		Format:     request.ResponseFormat,
+		header:     res.Header,
+	}
+	if response.Format == "" {
+		response.Format = SpeechFormatWAV
+	}
+	c.observe(string(request.Model), 0, nil, response.RateLimits())
+	if isFailureStatusCode(res) {
+		defer res.Body.Close()
+		return SpeechResponse{}, c.handleErrorResp(res)
+	}
+	return response, nil
+}
+
+// SpeechToFile synthesizes the input of the request into an audio file.
+//
+// The response format of the request defaults to the format of the
+// extension of the path, such as SpeechFormatMP3 for ".mp3", and must match
+// it.
+func (c *Client) SpeechToFile(
+	ctx context.Context,
+	request SpeechRequest,
+	path string,
+) error {
+	format, ok := speechExtensions[strings.ToLower(filepath.Ext(path))]
+	switch {
+	case !ok:
+	case request.ResponseFormat == "":
+		request.ResponseFormat = format
+	case request.ResponseFormat != format:
+		return fmt.Errorf(
+			"speech format %s does not match the file %s",
+			request.ResponseFormat,
+			path,
+		)
+	}
+	response, err := c.Speech(ctx, request)
+	if err != nil {
+		return err
+	}
+	return response.WriteFile(path)
+}
+
+// WriteFile writes the audio of the response to a file and closes the
+// response.
+//
+// The audio is written to a temporary file renamed to the path once
+// complete, so that the path never holds partial audio.
+func (r SpeechResponse) WriteFile(path string) error {
+	defer r.Close()
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".speech-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	err = tmp.Chmod(0o644)
+	if err == nil {
+		_, err = io.Copy(tmp, r)
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("writing speech: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,114 @@
+package groq_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// handleSpeechEndpoint handles the speech endpoint by replying with the
+// format of the request followed by its input as the audio.
+func handleSpeechEndpoint(w http.ResponseWriter, r *http.Request) {
+	var request groq.SpeechRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if request.Voice == "unknown" {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"message":"unknown voice","type":"invalid_request_error"}}`))
+		return
+	}
+	w.Header().Set("x-ratelimit-remaining-requests", "41")
+	w.Header().Set("Content-Type", "audio/"+string(request.ResponseFormat))
+	_, _ = w.Write([]byte(string(request.ResponseFormat) + ":" + request.Input))
+}
+
+// TestSpeech tests that the audio of the speech endpoint is streamed.
+func TestSpeech(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/audio/speech", handleSpeechEndpoint)
+
+	response, err := client.Speech(ctx, groq.SpeechRequest{
+		Model:          groq.ModelPlayaiTts,
+		Input:          "Hello there!",
+		Voice:          "Fritz-PlayAI",
+		ResponseFormat: groq.SpeechFormatMP3,
+		Speed:          1.5,
+	})
+	require.NoError(t, err)
+	defer response.Close()
+	audio, err := io.ReadAll(response)
+	require.NoError(t, err)
+	a.Equal("mp3:Hello there!", string(audio))
+	a.Equal(groq.SpeechFormatMP3, response.Format)
+	a.Equal(41, response.RateLimits().RemainingRequests)
+
+	_, err = client.Speech(ctx, groq.SpeechRequest{
+		Model: groq.ModelPlayaiTts,
+		Input: "Hello there!",
+		Voice: "unknown",
+	})
+	var apiErr *groqerr.APIError
+	require.ErrorAs(t, err, &apiErr)
+	a.Equal(http.StatusBadRequest, apiErr.HTTPStatusCode)
+
+	for _, request := range []groq.SpeechRequest{
+		{Model: groq.ModelPlayaiTts, Voice: "Fritz-PlayAI"},
+		{Model: groq.ModelPlayaiTts, Input: "Hello there!"},
+		{Model: groq.ModelPlayaiTts, Input: "Hello there!", Voice: "Fritz-PlayAI", Speed: 6},
+	} {
+		_, err = client.Speech(ctx, request)
+		a.Error(err)
+	}
+}
+
+// TestSpeechToFile tests that speech is written to files in the format of
+// their extension.
+func TestSpeechToFile(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/audio/speech", handleSpeechEndpoint)
+	dir := t.TempDir()
+	request := groq.SpeechRequest{
+		Model: groq.ModelPlayaiTts,
+		Input: "Hello there!",
+		Voice: "Fritz-PlayAI",
+	}
+
+	for file, want := range map[string]string{
+		"hello.mp3": "mp3:Hello there!",
+		"hello.WAV": "wav:Hello there!",
+	} {
+		path := filepath.Join(dir, file)
+		require.NoError(t, client.SpeechToFile(ctx, request, path))
+		audio, err := os.ReadFile(path)
+		require.NoError(t, err)
+		a.Equal(want, string(audio))
+	}
+
+	request.ResponseFormat = groq.SpeechFormatFLAC
+	a.Error(client.SpeechToFile(ctx, request, filepath.Join(dir, "hello.mp3")))
+	require.NoError(t, client.SpeechToFile(ctx, request, filepath.Join(dir, "hello.audio")))
+
+	request.Voice = "unknown"
+	a.Error(client.SpeechToFile(ctx, request, filepath.Join(dir, "unknown.flac")))
+	a.NoFileExists(filepath.Join(dir, "unknown.flac"))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	a.Len(entries, 3, "no temporary file should be left")
+}
@@ -0,0 +1,45 @@
+package groq
+
+import "context"
+
+// ChatCompletionStreamResult is a chunk received from a chat completion
+// stream or the error that ended it.
+type ChatCompletionStreamResult struct {
+	// Value is the received chunk.
+	Value *ChatCompletionStreamResponse
+	// Err is the error that ended the stream.
+	Err error
+}
+
+// Chan returns a channel receiving the remaining chunks of the stream.
+//
+// The channel is closed at the end of the stream, after the first error or
+// when the context is done, in which case the receiver should check the
+// context's error. The stream is closed along with the channel
+// so the underlying connection is released even if the receiver stops
+// reading.
+func (s *ChatCompletionStream) Chan(
+	ctx context.Context,
+) <-chan ChatCompletionStreamResult {
+	ch := make(chan ChatCompletionStreamResult)
+	go func() {
+		defer close(ch)
+		// closing the stream unblocks a pending read once ctx is done
+		stop := context.AfterFunc(ctx, func() { _ = s.Close() })
+		defer stop()
+		for response, err := range s.All() {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				err = ctxErr
+			}
+			select {
+			case ch <- ChatCompletionStreamResult{Value: response, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return ch
+}
@@ -0,0 +1,66 @@
+package groq
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/conneroisu/groq-go/internal/streams"
+	"github.com/stretchr/testify/assert"
+)
+
+// closeRecorder is a reader recording whether it has been closed.
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+// Close records the close.
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+// newTestStream returns a chat completion stream reading the body.
+func newTestStream(body io.ReadCloser) *ChatCompletionStream {
+	return &ChatCompletionStream{
+		StreamReader: streams.NewStreamReader[ChatCompletionStreamResponse](
+			body,
+			nil,
+			3,
+		),
+	}
+}
+
+// TestChatCompletionStreamChan tests that Chan delivers the chunks and
+// errors of the stream.
+func TestChatCompletionStreamChan(t *testing.T) {
+	a := assert.New(t)
+	body := &closeRecorder{Reader: bytes.NewReader([]byte(
+		"data: {\"id\":\"1\"}\n\ndata: {invalid\n\n",
+	))}
+	var results []ChatCompletionStreamResult
+	for result := range newTestStream(body).Chan(context.Background()) {
+		results = append(results, result)
+	}
+	a.Len(results, 2)
+	a.Equal("1", results[0].Value.ID)
+	a.Error(results[1].Err)
+	a.True(body.closed)
+}
+
+// TestChatCompletionStreamChanCancel tests that canceling the context
+// closes the channel and the stream while a read is pending.
+func TestChatCompletionStreamChanCancel(t *testing.T) {
+	a := assert.New(t)
+	reader, writer := io.Pipe()
+	defer writer.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := newTestStream(reader).Chan(ctx)
+	cancel()
+	for range ch {
+	}
+	_, err := reader.Read(make([]byte, 1))
+	a.ErrorIs(err, io.ErrClosedPipe)
+}
@@ -0,0 +1,49 @@
+package groq
+
+import "sort"
+
+// WordSegments returns the index in Segments of the segment of each word of
+// the response, or -1 for every word of a response without segments.
+//
+// A word belongs to the segment its middle falls in. Words falling between
+// segments, or before the first or after the last, belong to the nearest
+// one, as word and segment timestamps are not always aligned. Segments are
+// expected in order of time, as the api returns them.
+func (r AudioResponse) WordSegments() []int {
+	indices := make([]int, len(r.Words))
+	for i, word := range r.Words {
+		indices[i] = r.segmentAt((word.Start + word.End) / 2)
+	}
+	return indices
+}
+
+// SegmentWords returns the words of each segment of the response, in the
+// order of Segments, as WordSegments aligns them.
+func (r AudioResponse) SegmentWords() []Words {
+	words := make([]Words, len(r.Segments))
+	for i, segment := range r.WordSegments() {
+		if segment >= 0 {
+			words[segment] = append(words[segment], r.Words[i])
+		}
+	}
+	return words
+}
+
+// segmentAt returns the index of the segment nearest to the time at, or -1
+// without segments.
+func (r AudioResponse) segmentAt(at float64) int {
+	n := len(r.Segments)
+	if n == 0 {
+		return -1
+	}
+	i := sort.Search(n, func(i int) bool { return r.Segments[i].End > at })
+	switch {
+	case i == n:
+		return n - 1
+	case i > 0 && r.Segments[i].Start > at &&
+		at-r.Segments[i-1].End < r.Segments[i].Start-at:
+		return i - 1
+	default:
+		return i
+	}
+}
@@ -0,0 +1,94 @@
+package groq_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTimestampGranularities tests that the timestamp granularities of an
+// audio request are sent as a multi-valued form field.
+func TestTimestampGranularities(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/audio/transcriptions", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		a.Equal([]string{"word", "segment"}, r.MultipartForm.Value["timestamp_granularities[]"])
+		a.Equal(string(groq.FormatVerboseJSON), r.FormValue("response_format"))
+		_, _ = w.Write([]byte(`{
+			"task": "transcribe",
+			"text": "hello world",
+			"words": [{"word": "hello", "start": 0, "end": 0.4}, {"word": "world", "start": 0.5, "end": 0.9}],
+			"segments": [{"id": 0, "start": 0, "end": 1, "text": " hello world"}],
+			"x_groq": {"id": "req_123"}
+		}`))
+	})
+	request := groq.AudioRequest{
+		Model:    groq.ModelWhisperLargeV3,
+		FilePath: "audio.mp3",
+		Reader:   strings.NewReader("audio"),
+		TimestampGranularities: []groq.TimestampGranularity{
+			groq.TimestampGranularityWord,
+			groq.TimestampGranularitySegment,
+		},
+	}
+	response, err := client.Transcribe(ctx, request)
+	require.NoError(t, err)
+	a.Len(response.Words, 2)
+	a.Len(response.Segments, 1)
+	require.NotNil(t, response.XGroq)
+	a.Equal("req_123", response.XGroq.ID)
+
+	request.Reader = strings.NewReader("audio")
+	request.Format = groq.FormatText
+	_, err = client.Transcribe(ctx, request)
+	a.ErrorContains(err, "verbose_json")
+
+	request.Reader = strings.NewReader("audio")
+	request.Format = ""
+	request.TimestampGranularities = []groq.TimestampGranularity{"sentence"}
+	_, err = client.Transcribe(ctx, request)
+	a.ErrorContains(err, "sentence")
+}
+
+// TestWordSegments tests that words are aligned to the segments their
+// middle falls in or is nearest to.
+func TestWordSegments(t *testing.T) {
+	a := assert.New(t)
+	response := groq.AudioResponse{
+		Words: groq.Words{
+			{Word: "before", Start: 0, End: 0.2},
+			{Word: "one", Start: 0.5, End: 1},
+			{Word: "two", Start: 1.9, End: 2.3},
+			{Word: "gap", Start: 3.1, End: 3.3},
+			{Word: "three", Start: 4, End: 4.5},
+			{Word: "after", Start: 9, End: 9.5},
+		},
+		Segments: groq.Segments{
+			{ID: 0, Start: 0.4, End: 2.2},
+			{ID: 1, Start: 2.2, End: 3},
+			{ID: 2, Start: 3.8, End: 5},
+		},
+	}
+	a.Equal([]int{0, 0, 0, 1, 2, 2}, response.WordSegments())
+	segmentWords := response.SegmentWords()
+	require.Len(t, segmentWords, 3)
+	a.Len(segmentWords[0], 3)
+	a.Len(segmentWords[1], 1)
+	a.Equal("gap", segmentWords[1][0].Word)
+	a.Len(segmentWords[2], 2)
+
+	response.Segments = nil
+	a.Equal([]int{-1, -1, -1, -1, -1, -1}, response.WordSegments())
+	a.Empty(response.SegmentWords())
+}
@@ -0,0 +1,65 @@
+package groq
+
+import "unicode/utf8"
+
+const (
+	// charsPerToken is the average number of characters in a token used
+	// when estimating token counts.
+	charsPerToken = 4
+	// tokensPerMessage is the number of tokens each message adds to a
+	// prompt for its role and delimiters.
+	tokensPerMessage = 4
+	// tokensPerPrompt is the number of tokens added to every prompt to
+	// prime the assistant's reply.
+	tokensPerPrompt = 3
+	// tokensPerImage is the estimated number of tokens an image part adds
+	// to a prompt.
+	tokensPerImage = 1024
+)
+
+// EstimateTokens estimates the number of prompt tokens the given messages
+// will use.
+//
+// The estimate is a character based heuristic and does not depend on the
+// tokenizer of any specific model.
+func EstimateTokens(messages []ChatCompletionMessage) int {
+	tokens := tokensPerPrompt
+	for _, message := range messages {
+		tokens += EstimateMessageTokens(message)
+	}
+	return tokens
+}
+
+// EstimateMessageTokens estimates the number of tokens a single message
+// adds to a prompt.
+func EstimateMessageTokens(message ChatCompletionMessage) int {
+	tokens := tokensPerMessage +
+		estimateTextTokens(message.Name) +
+		estimateTextTokens(message.Content) +
+		estimateTextTokens(message.ToolCallID)
+	for _, part := range message.MultiContent {
+		switch part.Type {
+		case ChatMessagePartTypeImageURL:
+			tokens += tokensPerImage
+		default:
+			tokens += estimateTextTokens(part.Text)
+		}
+	}
+	if message.FunctionCall != nil {
+		tokens += estimateTextTokens(message.FunctionCall.Name) +
+			estimateTextTokens(message.FunctionCall.Arguments)
+	}
+	for _, call := range message.ToolCalls {
+		tokens += estimateTextTokens(call.Function.Name) +
+			estimateTextTokens(call.Function.Arguments)
+	}
+	return tokens
+}
+
+// estimateTextTokens estimates the number of tokens in a text.
+func estimateTextTokens(text string) int {
+	if text == "" {
+		return 0
+	}
+	return (utf8.RuneCountInString(text) + charsPerToken - 1) / charsPerToken
+}
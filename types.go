@@ -1,17 +1,20 @@
 package groq
 
 import (
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"os"
 	"time"
 
-	"github.com/conneroisu/groq-go/internal/schema"
 	"github.com/conneroisu/groq-go/internal/streams"
 	"github.com/conneroisu/groq-go/pkg/builders"
 	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"github.com/conneroisu/groq-go/pkg/jsonschema"
 	"github.com/conneroisu/groq-go/pkg/tools"
 )
 
@@ -25,7 +28,8 @@ const (
 	// FormatText is the text format.
 	FormatText Format = "text"
 	// FormatJSON is the JSON format.
-	// There is no support for streaming with JSON format selected.
+	// Streamed JSON output is decoded incrementally by
+	// ChatCompletionJSONStream.
 	FormatJSON Format = "json"
 	// FormatJSONObject is the json object chat
 	// completion response format type.
@@ -44,6 +48,19 @@ const (
 	FormatVerboseJSON Format = "verbose_json"
 )
 
+// TimestampGranularity is the granularity of the timestamps of a
+// transcription.
+type TimestampGranularity string
+
+const (
+	// TimestampGranularityWord timestamps the words of a transcription in
+	// the Words of its response.
+	TimestampGranularityWord TimestampGranularity = "word"
+	// TimestampGranularitySegment timestamps the segments of a
+	// transcription in the Segments of its response.
+	TimestampGranularitySegment TimestampGranularity = "segment"
+)
+
 // # [Chat](https://console.groq.com/docs/api-reference#chat-create)
 
 // ChatCompletionRequest represents a request structure for the chat
@@ -104,8 +121,13 @@ type (
 		StreamOptions *StreamOptions `json:"stream_options,omitempty"`
 		// Disable the default behavior of parallel tool calls by setting it: false.
 		ParallelToolCalls any `json:"parallel_tool_calls,omitempty"`
-		// RetryDelay is the delay between retries.
+		// RetryDelay overrides the initial backoff of the client's
+		// RetryPolicy for this request.
 		RetryDelay time.Duration `json:"-"`
+		// RepairAttempts is the number of times ChatCompletionJSON
+		// re-prompts the model with the validation errors of a reply
+		// that does not match the output's schema.
+		RepairAttempts int `json:"-"`
 	}
 	// ChatCompletionResponse represents a response structure for chat
 	// completion API.
@@ -124,7 +146,11 @@ type (
 		Usage Usage `json:"usage"`
 		// SystemFingerprint is the system fingerprint of the response.
 		SystemFingerprint string `json:"system_fingerprint"`
-		header            http.Header
+		// AnsweredBy is the model the request was sent to when it was
+		// answered, which differs from the requested model when a
+		// fallback model answered.
+		AnsweredBy ChatModel `json:"-"`
+		header     http.Header
 	}
 )
 
@@ -224,7 +250,7 @@ type (
 		Description string `json:"description,omitempty"`
 		// Schema is the schema of the chat completion response format
 		// json schema.
-		Schema schema.Schema `json:"schema"`
+		Schema jsonschema.Schema `json:"schema"`
 		// Strict determines whether to enforce the schema upon the
 		// generated content.
 		Strict bool `json:"strict"`
@@ -284,6 +310,9 @@ type (
 		Delta ChatCompletionStreamChoiceDelta `json:"delta"`
 		// FinishReason is the finish reason of the choice.
 		FinishReason FinishReason `json:"finish_reason"`
+		// LogProbs is the logarithmic probabilities of the tokens of the
+		// delta.
+		LogProbs *LogProbs `json:"logprobs,omitempty"`
 	}
 
 	// ChatCompletionStreamResponse represents a response structure for chat
@@ -321,6 +350,20 @@ type (
 		// chunk which contains the token usage statistics for the
 		// entire request.
 		Usage *Usage `json:"usage,omitempty"`
+		// XGroq is the groq specific metadata of the chat completion
+		// stream response.
+		//
+		// The last chunk of a stream carries the token usage statistics
+		// of the entire request in it.
+		XGroq *XGroq `json:"x_groq,omitempty"`
+	}
+	// XGroq is the groq specific metadata of a chat completion stream
+	// or audio response.
+	XGroq struct {
+		// ID is the groq id of the request.
+		ID string `json:"id,omitempty"`
+		// Usage is the usage of the entire request.
+		Usage *Usage `json:"usage,omitempty"`
 	}
 	// PromptAnnotation represents the prompt annotation.
 	PromptAnnotation struct {
@@ -343,6 +386,10 @@ type (
 	// ChatCompletionStream is a stream of ChatCompletionStreamResponse.
 	ChatCompletionStream struct {
 		*streams.StreamReader[*ChatCompletionStreamResponse]
+		// AnsweredBy is the model the stream was opened with, which
+		// differs from the requested model when a fallback model
+		// answered.
+		AnsweredBy ChatModel
 	}
 )
 
@@ -494,8 +541,20 @@ type (
 	//
 	// string
 	Moderation string
+	// ModerationResponse represents a response structure for the
+	// moderation of a chat history.
+	ModerationResponse struct {
+		// Categories are the categories of harmful content found in the
+		// chat history, empty when it is safe.
+		Categories []Moderation
+
+		header http.Header
+	}
 )
 
+// SetHeader sets the header of the response.
+func (r *ModerationResponse) SetHeader(header http.Header) { r.header = header }
+
 const (
 	// ModerationViolentCrimes (S1) is the violent crimes category.
 	//
@@ -586,6 +645,179 @@ var (
 	}
 )
 
+// # [Embeddings](https://console.groq.com/docs/api-reference#embeddings)
+
+type (
+	// EmbeddingModel is the type for embedding models present on the groq
+	// api.
+	EmbeddingModel Model
+	// EmbeddingEncodingFormat is the format of the returned embedding
+	// vectors.
+	//
+	// string
+	EmbeddingEncodingFormat string
+	// EmbeddingRequest represents a request structure for the embeddings
+	// API.
+	EmbeddingRequest struct {
+		// Input is the input to embed.
+		//
+		// It must be one of string, []string, []int or [][]int where the
+		// integer forms are pre-tokenized inputs.
+		Input any `json:"input"`
+		// Model is the model to use for the embeddings.
+		Model EmbeddingModel `json:"model"`
+		// EncodingFormat is the format of the returned embeddings.
+		//
+		// Defaults to EmbeddingEncodingFormatFloat.
+		EncodingFormat EmbeddingEncodingFormat `json:"encoding_format,omitempty"`
+		// Dimensions is the number of dimensions the resulting output
+		// embeddings should have.
+		Dimensions int `json:"dimensions,omitempty"`
+		// User is the user of the embeddings request.
+		User string `json:"user,omitempty"`
+	}
+	// EmbeddingResponse represents a response structure for the
+	// embeddings API.
+	EmbeddingResponse struct {
+		// Object is the object of the response.
+		Object string `json:"object"`
+		// Data is the list of embeddings of the response.
+		Data []Embedding `json:"data"`
+		// Model is the model of the response.
+		Model EmbeddingModel `json:"model"`
+		// Usage is the usage of the response.
+		Usage Usage `json:"usage"`
+
+		header http.Header
+	}
+	// Embedding is a single embedding vector returned by the embeddings
+	// API.
+	Embedding struct {
+		// Object is the object of the embedding.
+		Object string `json:"object"`
+		// Index is the index of the input the embedding belongs to.
+		Index int `json:"index"`
+		// Embedding is the embedding vector.
+		//
+		// Base64 encoded vectors are decoded into float32 values.
+		Embedding []float32 `json:"embedding"`
+	}
+)
+
+const (
+	// EmbeddingEncodingFormatFloat returns the embeddings as a list of
+	// floats.
+	EmbeddingEncodingFormatFloat EmbeddingEncodingFormat = "float"
+	// EmbeddingEncodingFormatBase64 returns the embeddings as base64
+	// encoded little-endian float32 values.
+	EmbeddingEncodingFormatBase64 EmbeddingEncodingFormat = "base64"
+)
+
+// SetHeader sets the header of the response.
+func (r *EmbeddingResponse) SetHeader(h http.Header) { r.header = h }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+//
+// It exists to decode both float list and base64 encoded embeddings.
+func (e *Embedding) UnmarshalJSON(bs []byte) error {
+	raw := struct {
+		Object    string          `json:"object"`
+		Index     int             `json:"index"`
+		Embedding json.RawMessage `json:"embedding"`
+	}{}
+	err := json.Unmarshal(bs, &raw)
+	if err != nil {
+		return err
+	}
+	e.Object = raw.Object
+	e.Index = raw.Index
+	e.Embedding = nil
+	if len(raw.Embedding) == 0 || string(raw.Embedding) == "null" {
+		return nil
+	}
+	if raw.Embedding[0] != '"' {
+		return json.Unmarshal(raw.Embedding, &e.Embedding)
+	}
+	var encoded string
+	err = json.Unmarshal(raw.Embedding, &encoded)
+	if err != nil {
+		return err
+	}
+	e.Embedding, err = decodeBase64Embedding(encoded)
+	return err
+}
+
+// decodeBase64Embedding decodes a base64 string of little-endian float32
+// values.
+func decodeBase64Embedding(encoded string) ([]float32, error) {
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decoding base64 embedding: %w", err)
+	}
+	if len(decoded)%4 != 0 {
+		return nil, fmt.Errorf(
+			"decoding base64 embedding: invalid length %d",
+			len(decoded),
+		)
+	}
+	vector := make([]float32, len(decoded)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(
+			binary.LittleEndian.Uint32(decoded[i*4:]),
+		)
+	}
+	return vector, nil
+}
+
+// validate validates the input of the embedding request.
+func (r EmbeddingRequest) validate() error {
+	switch in := r.Input.(type) {
+	case string:
+		if in == "" {
+			return fmt.Errorf("embedding input cannot be empty")
+		}
+	case []string:
+		if len(in) == 0 {
+			return fmt.Errorf("embedding input cannot be empty")
+		}
+	case []int:
+		if len(in) == 0 {
+			return fmt.Errorf("embedding input cannot be empty")
+		}
+	case [][]int:
+		if len(in) == 0 {
+			return fmt.Errorf("embedding input cannot be empty")
+		}
+	default:
+		return fmt.Errorf("unsupported embedding input type %T", r.Input)
+	}
+	return nil
+}
+
+// estimateTokens estimates the number of tokens of the embedding input.
+func (r EmbeddingRequest) estimateTokens() int {
+	switch in := r.Input.(type) {
+	case string:
+		return estimateTextTokens(in)
+	case []string:
+		tokens := 0
+		for _, s := range in {
+			tokens += estimateTextTokens(s)
+		}
+		return tokens
+	case []int:
+		return len(in)
+	case [][]int:
+		tokens := 0
+		for _, ids := range in {
+			tokens += len(ids)
+		}
+		return tokens
+	default:
+		return 0
+	}
+}
+
 // # [Audio](https://console.groq.com/docs/api-reference#audio-transcription)
 
 type (
@@ -608,6 +840,12 @@ type (
 		Language string
 		// Format is the format for the response.
 		Format Format
+		// TimestampGranularities are the granularities of the timestamps
+		// of the response. They require the verbose json format, which
+		// they default the format to.
+		//
+		// Word timestamps add latency, while segment timestamps do not.
+		TimestampGranularities []TimestampGranularity
 	}
 	// AudioResponse represents a response structure for audio API.
 	AudioResponse struct {
@@ -623,6 +861,8 @@ type (
 		Words Words `json:"words"`
 		// Text is the text of the response.
 		Text string `json:"text"`
+		// XGroq is the groq specific metadata of the response.
+		XGroq *XGroq `json:"x_groq,omitempty"`
 
 		header http.Header `json:"-"`
 	}
@@ -727,13 +967,40 @@ func audioMultipartForm(request AudioRequest, b builders.FormBuilder) error {
 			return fmt.Errorf("writing prompt: %w", err)
 		}
 	}
+	format := request.Format
+	if len(request.TimestampGranularities) > 0 {
+		switch format {
+		case "":
+			format = FormatVerboseJSON
+		case FormatVerboseJSON:
+		default:
+			return fmt.Errorf(
+				"timestamp granularities require the %s format, not %s",
+				FormatVerboseJSON,
+				format,
+			)
+		}
+	}
 	// Create a form field for the format (if provided)
-	if request.Format != "" {
-		err = b.WriteField("response_format", string(request.Format))
+	if format != "" {
+		err = b.WriteField("response_format", string(format))
 		if err != nil {
 			return fmt.Errorf("writing format: %w", err)
 		}
 	}
+	// Create a form field per timestamp granularity, the brackets of the
+	// field name telling the api that it has several values
+	for _, granularity := range request.TimestampGranularities {
+		switch granularity {
+		case TimestampGranularityWord, TimestampGranularitySegment:
+		default:
+			return fmt.Errorf("unknown timestamp granularity: %q", granularity)
+		}
+		err = b.WriteField("timestamp_granularities[]", string(granularity))
+		if err != nil {
+			return fmt.Errorf("writing timestamp granularity: %w", err)
+		}
+	}
 	// Create a form field for the temperature (if provided)
 	if request.Temperature != 0 {
 		err = b.WriteField(
@@ -753,3 +1020,87 @@ func audioMultipartForm(request AudioRequest, b builders.FormBuilder) error {
 	}
 	return b.Close()
 }
+
+// # [Speech](https://console.groq.com/docs/api-reference#audio-speech)
+
+// SpeechFormat is the audio format of synthesized speech.
+type SpeechFormat string
+
+const (
+	// SpeechFormatWAV is the wav speech format, the default of the api.
+	SpeechFormatWAV SpeechFormat = "wav"
+	// SpeechFormatMP3 is the mp3 speech format.
+	SpeechFormatMP3 SpeechFormat = "mp3"
+	// SpeechFormatFLAC is the flac speech format.
+	SpeechFormatFLAC SpeechFormat = "flac"
+	// SpeechFormatOgg is the ogg speech format.
+	SpeechFormatOgg SpeechFormat = "ogg"
+	// SpeechFormatMulaw is the mu-law speech format.
+	SpeechFormatMulaw SpeechFormat = "mulaw"
+)
+
+const (
+	// MinSpeechSpeed is the slowest speed of synthesized speech.
+	MinSpeechSpeed = 0.5
+	// MaxSpeechSpeed is the fastest speed of synthesized speech.
+	MaxSpeechSpeed = 5.0
+)
+
+type (
+	// SpeechRequest represents a request structure for the speech API.
+	SpeechRequest struct {
+		// Model is the text to speech model to use.
+		Model SpeechModel `json:"model"`
+		// Input is the text to synthesize.
+		Input string `json:"input"`
+		// Voice is the voice of the speech, such as "Fritz-PlayAI" for
+		// ModelPlayaiTts or "Ahmad-PlayAI" for ModelPlayaiTtsArabic.
+		Voice string `json:"voice"`
+		// ResponseFormat is the audio format of the speech.
+		//
+		// Defaults to SpeechFormatWAV.
+		ResponseFormat SpeechFormat `json:"response_format,omitempty"`
+		// Speed is the speed of the speech between MinSpeechSpeed and
+		// MaxSpeechSpeed.
+		//
+		// Defaults to 1.
+		Speed float64 `json:"speed,omitempty"`
+		// SampleRate is the sample rate of the speech in hertz.
+		//
+		// Defaults to 48000.
+		SampleRate int `json:"sample_rate,omitempty"`
+	}
+	// SpeechResponse represents a response structure for the speech API.
+	//
+	// It streams the audio of the speech as it is synthesized and must be
+	// closed.
+	SpeechResponse struct {
+		io.ReadCloser
+		// Format is the audio format of the speech.
+		Format SpeechFormat
+
+		header http.Header
+	}
+)
+
+// SetHeader sets the header of the response.
+func (r *SpeechResponse) SetHeader(header http.Header) { r.header = header }
+
+// validate validates the speech request.
+func (r SpeechRequest) validate() error {
+	if r.Input == "" {
+		return fmt.Errorf("speech input cannot be empty")
+	}
+	if r.Voice == "" {
+		return fmt.Errorf("speech voice cannot be empty")
+	}
+	if r.Speed != 0 && (r.Speed < MinSpeechSpeed || r.Speed > MaxSpeechSpeed) {
+		return fmt.Errorf(
+			"speech speed %v is not between %v and %v",
+			r.Speed,
+			MinSpeechSpeed,
+			MaxSpeechSpeed,
+		)
+	}
+	return nil
+}
@@ -131,7 +131,7 @@ func handleModerationEndpoint(w http.ResponseWriter, _ *http.Request) {
 	}
 }
 
-func setupGroqTestServer() (
+func setupGroqTestServer(opts ...groq.Opts) (
 	client *groq.Client,
 	server *test.ServerTest,
 	teardown func(),
@@ -142,7 +142,7 @@ func setupGroqTestServer() (
 	teardown = ts.Close
 	client, err := groq.NewClient(
 		test.GetTestToken(),
-		groq.WithBaseURL(ts.URL+"/v1"),
+		append([]groq.Opts{groq.WithBaseURL(ts.URL + "/v1")}, opts...)...,
 	)
 	if err != nil {
 		log.Fatal(err)
@@ -894,3 +894,242 @@ func handleSignifyTaskCompletionErrorEndpoint(w http.ResponseWriter, r *http.Req
 		return
 	}
 }
+
+// TestEmbeddings tests the Embeddings method of the client.
+func TestEmbeddings(t *testing.T) {
+	a := assert.New(t)
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	server.RegisterHandler(
+		"/v1/embeddings",
+		func(w http.ResponseWriter, r *http.Request) {
+			var req map[string]any
+			err := json.NewDecoder(r.Body).Decode(&req)
+			a.NoError(err, "Decode error")
+			a.Equal([]any{"hello", "world"}, req["input"])
+			a.EqualValues(2, req["dimensions"])
+			_, err = w.Write([]byte(`{
+				"object": "list",
+				"data": [
+					{"object": "embedding", "index": 0, "embedding": [0.5, -1.25]},
+					{"object": "embedding", "index": 1, "embedding": [1, 2]}
+				],
+				"model": "test-embed",
+				"usage": {"prompt_tokens": 2, "total_tokens": 2}
+			}`))
+			a.NoError(err, "Write error")
+		},
+	)
+	resp, err := client.Embeddings(context.Background(), groq.EmbeddingRequest{
+		Input:      []string{"hello", "world"},
+		Model:      "test-embed",
+		Dimensions: 2,
+	})
+	a.NoError(err, "Embeddings error")
+	a.Len(resp.Data, 2)
+	a.Equal([]float32{0.5, -1.25}, resp.Data[0].Embedding)
+	a.Equal([]float32{1, 2}, resp.Data[1].Embedding)
+	a.Equal(2, resp.Usage.PromptTokens)
+}
+
+// TestEmbeddingsBase64 tests that base64 encoded embeddings are decoded.
+func TestEmbeddingsBase64(t *testing.T) {
+	a := assert.New(t)
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	// 0.5 and -1.25 as little-endian float32 values.
+	encoded := "AAAAPwAAoL8="
+	server.RegisterHandler(
+		"/v1/embeddings",
+		func(w http.ResponseWriter, _ *http.Request) {
+			_, err := w.Write([]byte(`{"object":"list","data":[{"object":"embedding","index":0,"embedding":"` + encoded + `"}],"model":"test-embed","usage":{"prompt_tokens":1,"total_tokens":1}}`))
+			a.NoError(err, "Write error")
+		},
+	)
+	resp, err := client.Embeddings(context.Background(), groq.EmbeddingRequest{
+		Input:          "hello",
+		Model:          "test-embed",
+		EncodingFormat: groq.EmbeddingEncodingFormatBase64,
+	})
+	a.NoError(err, "Embeddings error")
+	a.Equal([]float32{0.5, -1.25}, resp.Data[0].Embedding)
+}
+
+// TestEmbeddingsInvalidInput tests that unsupported inputs are rejected.
+func TestEmbeddingsInvalidInput(t *testing.T) {
+	a := assert.New(t)
+	client, _, teardown := setupGroqTestServer()
+	defer teardown()
+	_, err := client.Embeddings(context.Background(), groq.EmbeddingRequest{
+		Input: 42,
+		Model: "test-embed",
+	})
+	a.Error(err, "Embeddings should reject unsupported input")
+}
+
+// TestRateLimits tests that the rate limit headers are parsed on every
+// response type.
+func TestRateLimits(t *testing.T) {
+	a := assert.New(t)
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	setHeaders := func(w http.ResponseWriter) {
+		w.Header().Set("x-ratelimit-limit-requests", "14400")
+		w.Header().Set("x-ratelimit-limit-tokens", "18000")
+		w.Header().Set("x-ratelimit-remaining-requests", "14370")
+		w.Header().Set("x-ratelimit-remaining-tokens", "17997")
+		w.Header().Set("x-ratelimit-reset-requests", "2m59.56s")
+		w.Header().Set("x-ratelimit-reset-tokens", "7.66s")
+	}
+	expected := groq.RateLimits{
+		LimitRequests:     14400,
+		LimitTokens:       18000,
+		RemainingRequests: 14370,
+		RemainingTokens:   17997,
+		ResetRequests:     2*time.Minute + 59560*time.Millisecond,
+		ResetTokens:       7660 * time.Millisecond,
+	}
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		func(w http.ResponseWriter, r *http.Request) {
+			setHeaders(w)
+			var req groq.ChatCompletionRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if req.Stream {
+				w.Header().Set("Content-Type", "text/event-stream")
+				_, _ = w.Write([]byte("data: [DONE]\n\n"))
+				return
+			}
+			_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"hi"}}]}`))
+		},
+	)
+	server.RegisterHandler(
+		"/v1/audio/transcriptions",
+		func(w http.ResponseWriter, _ *http.Request) {
+			setHeaders(w)
+			_, _ = w.Write([]byte(`{"text":"hello"}`))
+		},
+	)
+	ctx := context.Background()
+	req := groq.ChatCompletionRequest{
+		Model: groq.ModelLlama38B8192,
+		Messages: []groq.ChatCompletionMessage{
+			{Role: groq.RoleUser, Content: "Hello!"},
+		},
+	}
+	resp, err := client.ChatCompletion(ctx, req)
+	a.NoError(err)
+	a.Equal(expected, resp.RateLimits())
+	stream, err := client.ChatCompletionStream(ctx, req)
+	a.NoError(err)
+	defer stream.Close()
+	a.Equal(expected, stream.RateLimits())
+	audio, err := client.Transcribe(ctx, groq.AudioRequest{
+		Model:    groq.ModelWhisperLargeV3,
+		FilePath: "audio.mp3",
+		Reader:   strings.NewReader("hello"),
+	})
+	a.NoError(err)
+	a.Equal(expected, audio.RateLimits())
+	moderation, err := client.ModerateWithResponse(ctx, req.Messages, groq.ModelLlamaGuard38B)
+	a.NoError(err)
+	a.Equal(expected, moderation.RateLimits())
+
+	server.RegisterHandler(
+		"/v1/models",
+		func(w http.ResponseWriter, _ *http.Request) {
+			setHeaders(w)
+			_, _ = w.Write([]byte(`{"object":"list","data":[]}`))
+		},
+	)
+	server.RegisterHandler(
+		"/v1/models/whisper-large-v3",
+		func(w http.ResponseWriter, _ *http.Request) {
+			setHeaders(w)
+			_, _ = w.Write([]byte(`{"id":"whisper-large-v3","object":"model"}`))
+		},
+	)
+	list, err := client.ListModels(ctx)
+	a.NoError(err)
+	a.Equal(expected, list.RateLimits())
+	details, err := client.GetModel(ctx, groq.Model(groq.ModelWhisperLargeV3))
+	a.NoError(err)
+	a.Equal(expected, details.RateLimits())
+}
+
+// TestParseRateLimitsRetryAfter tests that the retry-after header is parsed.
+func TestParseRateLimitsRetryAfter(t *testing.T) {
+	a := assert.New(t)
+	limits := groq.ParseRateLimits(http.Header{
+		"Retry-After": []string{"2"},
+	})
+	a.Equal(2*time.Second, limits.RetryAfter)
+	a.Zero(limits.RemainingTokens)
+}
+
+// jsonReply returns a chat completion replying with the given content.
+func jsonReply(content string) groq.ChatCompletionResponse {
+	return groq.ChatCompletionResponse{
+		ID: "chatcmpl-json",
+		Choices: []groq.ChatCompletionChoice{{
+			Message: groq.ChatCompletionMessage{
+				Role:    groq.RoleAssistant,
+				Content: content,
+			},
+			FinishReason: groq.ReasonStop,
+		}},
+	}
+}
+
+// TestChatCompletionJSONRepair tests that replies not matching the schema
+// are repaired by re-prompting the model with the violations.
+func TestChatCompletionJSONRepair(t *testing.T) {
+	a := assert.New(t)
+	client, requests, teardown := setupAgentTestServer(t,
+		jsonReply(`{"name":"Ada","age":"36"}`),
+		jsonReply("```json\n{\"name\":\"Ada\",\"age\":36,\"hobbies\":[]}\n```"),
+	)
+	defer teardown()
+	messages := []groq.ChatCompletionMessage{
+		{Role: groq.RoleUser, Content: "Who is Ada?"},
+	}
+	var person streamedPerson
+	err := client.ChatCompletionJSON(context.Background(), groq.ChatCompletionRequest{
+		Model:          groq.ModelLlama38B8192,
+		Messages:       messages,
+		RepairAttempts: 1,
+	}, &person)
+	a.NoError(err)
+	a.Equal(streamedPerson{Name: "Ada", Age: 36, Hobbies: []string{}}, person)
+	a.Len(*requests, 2)
+	repair := (*requests)[1].Messages
+	a.Len(repair, 3)
+	a.Equal(groq.RoleAssistant, repair[1].Role)
+	a.Contains(repair[2].Content, "$.age: expected integer, got string")
+	a.Contains(repair[2].Content, "$.hobbies: is required")
+	a.Len(messages, 1)
+}
+
+// TestChatCompletionJSONValidationError tests that a typed error listing
+// every violation is returned once the repair attempts are spent.
+func TestChatCompletionJSONValidationError(t *testing.T) {
+	a := assert.New(t)
+	client, requests, teardown := setupAgentTestServer(t,
+		jsonReply(`{"name":"Ada","age":36,"hobbies":[],"extra":1}`),
+	)
+	defer teardown()
+	var person streamedPerson
+	err := client.ChatCompletionJSON(context.Background(), groq.ChatCompletionRequest{
+		Model: groq.ModelLlama38B8192,
+		Messages: []groq.ChatCompletionMessage{
+			{Role: groq.RoleUser, Content: "Who is Ada?"},
+		},
+	}, &person)
+	var validationErr groqerr.ErrSchemaValidation
+	a.True(errors.As(err, &validationErr))
+	a.Equal([]groqerr.SchemaViolation{{
+		Path:    "$.extra",
+		Message: "additional property is not allowed",
+	}}, validationErr.Violations)
+	a.Len(*requests, 1)
+}
@@ -0,0 +1,274 @@
+package groq
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"net/http"
+	"os"
+
+	// register the decoders of the formats accepted by the vision models
+	_ "image/gif"
+
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+)
+
+const (
+	// MaxImagesPerRequest is the number of image parts accepted by the
+	// vision models per request.
+	MaxImagesPerRequest = 5
+	// MaxImageBytes is the size limit of the base64 data url of an image.
+	MaxImageBytes = 4 << 20
+	// MaxImagePixels is the resolution limit of an image in pixels.
+	MaxImagePixels = 33_177_600
+	// jpegQuality is the quality images are re-encoded with as jpeg.
+	jpegQuality = 85
+)
+
+type (
+	// ImageOpts is a function that sets options for building an image part.
+	ImageOpts func(*imageConfig)
+	// imageConfig is the configuration of an image part.
+	imageConfig struct {
+		detail    ImageURLDetail
+		maxBytes  int
+		maxPixels int
+	}
+)
+
+// WithImageDetail sets the detail of an image part.
+func WithImageDetail(detail ImageURLDetail) ImageOpts {
+	return func(c *imageConfig) { c.detail = detail }
+}
+
+// WithMaxImageBytes sets the size limit of the data url of an image part,
+// MaxImageBytes by default.
+func WithMaxImageBytes(n int) ImageOpts {
+	return func(c *imageConfig) { c.maxBytes = n }
+}
+
+// WithMaxImagePixels sets the resolution limit of an image part,
+// MaxImagePixels by default.
+func WithMaxImagePixels(n int) ImageOpts {
+	return func(c *imageConfig) { c.maxPixels = n }
+}
+
+// VisionMessage returns a user message asking about images with text.
+func VisionMessage(text string, images ...ChatMessagePart) ChatCompletionMessage {
+	return ChatCompletionMessage{
+		Role: RoleUser,
+		MultiContent: append([]ChatMessagePart{{
+			Type: ChatMessagePartTypeText,
+			Text: text,
+		}}, images...),
+	}
+}
+
+// ImagePartFromFile returns an image part with the image of a file as a
+// data url.
+//
+// See ImagePartFromReader for how the image is processed.
+func ImagePartFromFile(path string, opts ...ImageOpts) (ChatMessagePart, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ChatMessagePart{}, err
+	}
+	defer f.Close()
+	return ImagePartFromReader(f, opts...)
+}
+
+// ImagePartFromReader returns an image part with the image read from r as
+// a data url.
+//
+// The mime type of the image is sniffed from its content. Jpeg, png, gif
+// and webp images within the size and resolution limits are sent as is.
+// Larger jpeg, png and gif images are downscaled and re-encoded as jpeg, or
+// png if they have transparency, while larger webp images fail with a
+// groqerr.ErrImageTooLarge.
+func ImagePartFromReader(r io.Reader, opts ...ImageOpts) (ChatMessagePart, error) {
+	cfg := newImageConfig(opts)
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return ChatMessagePart{}, err
+	}
+	mimeType := http.DetectContentType(b)
+	switch mimeType {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+	default:
+		return ChatMessagePart{}, groqerr.ErrUnsupportedImage{MIMEType: mimeType}
+	}
+	fits := dataURLLen(mimeType, len(b)) <= cfg.maxBytes
+	config, _, err := image.DecodeConfig(bytes.NewReader(b))
+	if err != nil {
+		if mimeType != "image/webp" {
+			return ChatMessagePart{}, err
+		}
+		// webp can not be decoded without extra dependencies
+		if !fits {
+			return ChatMessagePart{}, groqerr.ErrImageTooLarge{
+				Size:  dataURLLen(mimeType, len(b)),
+				Limit: cfg.maxBytes,
+			}
+		}
+		return cfg.part(mimeType, b), nil
+	}
+	if fits && config.Width*config.Height <= cfg.maxPixels {
+		return cfg.part(mimeType, b), nil
+	}
+	img, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return ChatMessagePart{}, err
+	}
+	return cfg.encode(img)
+}
+
+// ImagePart returns an image part with the image encoded as a data url.
+//
+// The image is downscaled to fit the size and resolution limits and
+// encoded as jpeg, or png if it has transparency.
+func ImagePart(img image.Image, opts ...ImageOpts) (ChatMessagePart, error) {
+	return newImageConfig(opts).encode(img)
+}
+
+// newImageConfig returns the configuration of an image part.
+func newImageConfig(opts []ImageOpts) imageConfig {
+	cfg := imageConfig{
+		detail:    ImageURLDetailAuto,
+		maxBytes:  MaxImageBytes,
+		maxPixels: MaxImagePixels,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// part returns the image part of an encoded image.
+func (c imageConfig) part(mimeType string, b []byte) ChatMessagePart {
+	return ChatMessagePart{
+		Type: ChatMessagePartTypeImageURL,
+		ImageURL: &ChatMessageImageURL{
+			URL:    "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(b),
+			Detail: c.detail,
+		},
+	}
+}
+
+// encode downscales the image to the resolution limit and then by a
+// quarter at a time until its encoding fits the size limit.
+func (c imageConfig) encode(img image.Image) (ChatMessagePart, error) {
+	src := toRGBA(img)
+	width, height := src.Bounds().Dx(), src.Bounds().Dy()
+	if width*height > c.maxPixels {
+		scale := math.Sqrt(float64(c.maxPixels) / float64(width*height))
+		width = max(1, int(float64(width)*scale))
+		height = max(1, int(float64(height)*scale))
+	}
+	for {
+		mimeType, b, err := encodeImage(resize(src, width, height))
+		if err != nil {
+			return ChatMessagePart{}, err
+		}
+		size := dataURLLen(mimeType, len(b))
+		if size <= c.maxBytes {
+			return c.part(mimeType, b), nil
+		}
+		if width == 1 && height == 1 {
+			return ChatMessagePart{}, groqerr.ErrImageTooLarge{
+				Size:  size,
+				Limit: c.maxBytes,
+			}
+		}
+		width = max(1, width*3/4)
+		height = max(1, height*3/4)
+	}
+}
+
+// dataURLLen returns the length of the data url of an encoded image.
+func dataURLLen(mimeType string, n int) int {
+	return len("data:;base64,") + len(mimeType) + base64.StdEncoding.EncodedLen(n)
+}
+
+// encodeImage encodes an image as png if it has transparency and as jpeg
+// otherwise.
+func encodeImage(img *image.RGBA) (string, []byte, error) {
+	var buf bytes.Buffer
+	if !img.Opaque() {
+		err := png.Encode(&buf, img)
+		return "image/png", buf.Bytes(), err
+	}
+	err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	return "image/jpeg", buf.Bytes(), err
+}
+
+// toRGBA converts an image to an RGBA image whose bounds start at the
+// origin.
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba
+}
+
+// resize downscales an image to width by height pixels, averaging the
+// source pixels covered by each destination pixel.
+func resize(src *image.RGBA, width, height int) *image.RGBA {
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+	if width >= srcWidth && height >= srcHeight {
+		return src
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		y0, y1 := y*srcHeight/height, max((y+1)*srcHeight/height, y*srcHeight/height+1)
+		for x := range width {
+			x0, x1 := x*srcWidth/width, max((x+1)*srcWidth/width, x*srcWidth/width+1)
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride+x0*4 : sy*src.Stride+x1*4]
+				for i, v := range row {
+					sum[i%4] += int(v)
+				}
+			}
+			n := (y1 - y0) * (x1 - x0)
+			offset := dst.PixOffset(x, y)
+			for i := range sum {
+				dst.Pix[offset+i] = uint8(sum[i] / n)
+			}
+		}
+	}
+	return dst
+}
+
+// checkImages returns an error if a request has image parts and its model
+// lacks vision or the request has more images than MaxImagesPerRequest.
+//
+// Models missing from the registry are only checked for the image count.
+func checkImages(request ChatCompletionRequest) error {
+	count := 0
+	for _, message := range request.Messages {
+		for _, part := range message.MultiContent {
+			if part.Type == ChatMessagePartTypeImageURL || part.ImageURL != nil {
+				count++
+			}
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	info, ok := ModelInfoFor(request.Model)
+	if ok && !info.HasCapability(CapabilityVision) {
+		return groqerr.ErrVisionUnsupported{Model: string(request.Model)}
+	}
+	if count > MaxImagesPerRequest {
+		return groqerr.ErrTooManyImages{Count: count, Max: MaxImagesPerRequest}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package groq_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gradient returns an opaque image of width by height pixels.
+func gradient(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: uint8(x ^ y), A: 255})
+		}
+	}
+	return img
+}
+
+// decodePart decodes the data url of an image part.
+func decodePart(t *testing.T, part groq.ChatMessagePart) (string, image.Image) {
+	t.Helper()
+	require.Equal(t, groq.ChatMessagePartTypeImageURL, part.Type)
+	mimeType, data, ok := strings.Cut(strings.TrimPrefix(part.ImageURL.URL, "data:"), ";base64,")
+	require.True(t, ok)
+	b, err := base64.StdEncoding.DecodeString(data)
+	require.NoError(t, err)
+	img, _, err := image.Decode(bytes.NewReader(b))
+	require.NoError(t, err)
+	return mimeType, img
+}
+
+// TestImagePartFromReader tests that images within the limits are sent as
+// is and larger images are downscaled.
+func TestImagePartFromReader(t *testing.T) {
+	a := assert.New(t)
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, gradient(40, 20)))
+
+	part, err := groq.ImagePartFromReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	a.Equal(
+		"data:image/png;base64,"+base64.StdEncoding.EncodeToString(buf.Bytes()),
+		part.ImageURL.URL,
+	)
+	a.Equal(groq.ImageURLDetailAuto, part.ImageURL.Detail)
+
+	part, err = groq.ImagePartFromReader(
+		bytes.NewReader(buf.Bytes()),
+		groq.WithMaxImagePixels(200),
+		groq.WithImageDetail(groq.ImageURLDetailLow),
+	)
+	require.NoError(t, err)
+	mimeType, img := decodePart(t, part)
+	a.Equal("image/jpeg", mimeType)
+	a.Equal(image.Rect(0, 0, 20, 10), img.Bounds())
+	a.Equal(groq.ImageURLDetailLow, part.ImageURL.Detail)
+
+	_, err = groq.ImagePartFromReader(strings.NewReader("not an image"))
+	var unsupported groqerr.ErrUnsupportedImage
+	require.ErrorAs(t, err, &unsupported)
+	a.Equal("text/plain; charset=utf-8", unsupported.MIMEType)
+}
+
+// TestImagePart tests the size limit and transparency of encoded images.
+func TestImagePart(t *testing.T) {
+	a := assert.New(t)
+	img := gradient(256, 256)
+	part, err := groq.ImagePart(img, groq.WithMaxImageBytes(4000))
+	require.NoError(t, err)
+	a.LessOrEqual(len(part.ImageURL.URL), 4000)
+	_, resized := decodePart(t, part)
+	a.Less(resized.Bounds().Dx(), 256)
+
+	img.Set(0, 0, color.NRGBA{})
+	part, err = groq.ImagePart(img)
+	require.NoError(t, err)
+	mimeType, _ := decodePart(t, part)
+	a.Equal("image/png", mimeType)
+
+	_, err = groq.ImagePart(img, groq.WithMaxImageBytes(10))
+	var tooLarge groqerr.ErrImageTooLarge
+	require.ErrorAs(t, err, &tooLarge)
+	a.Equal(10, tooLarge.Limit)
+}
+
+// TestImagePartFromFile tests building a vision message from a file.
+func TestImagePartFromFile(t *testing.T) {
+	a := assert.New(t)
+	path := filepath.Join(t.TempDir(), "image.png")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, png.Encode(f, gradient(8, 8)))
+	require.NoError(t, f.Close())
+	part, err := groq.ImagePartFromFile(path)
+	require.NoError(t, err)
+	message := groq.VisionMessage("what is this?", part)
+	a.Equal(groq.RoleUser, message.Role)
+	require.Len(t, message.MultiContent, 2)
+	a.Equal("what is this?", message.MultiContent[0].Text)
+	a.Equal(part, message.MultiContent[1])
+
+	_, err = groq.ImagePartFromFile(filepath.Join(t.TempDir(), "missing.png"))
+	a.ErrorIs(err, os.ErrNotExist)
+}
+
+// TestVisionChecks tests that image parts are checked against the model
+// before the request is sent.
+func TestVisionChecks(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	calls := 0
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		_ = json.NewEncoder(w).Encode(jsonReply("hi"))
+	})
+	part, err := groq.ImagePart(gradient(8, 8))
+	require.NoError(t, err)
+
+	_, err = client.ChatCompletion(ctx, groq.ChatCompletionRequest{
+		Model:    groq.ModelLlama38B8192,
+		Messages: []groq.ChatCompletionMessage{groq.VisionMessage("hi", part)},
+	})
+	var unsupported groqerr.ErrVisionUnsupported
+	require.ErrorAs(t, err, &unsupported)
+	a.Equal(string(groq.ModelLlama38B8192), unsupported.Model)
+
+	images := make([]groq.ChatMessagePart, groq.MaxImagesPerRequest+1)
+	for i := range images {
+		images[i] = part
+	}
+	_, err = client.ChatCompletionStream(ctx, groq.ChatCompletionRequest{
+		Model:    groq.ModelLlama3211BVisionPreview,
+		Messages: []groq.ChatCompletionMessage{groq.VisionMessage("hi", images...)},
+	})
+	var tooMany groqerr.ErrTooManyImages
+	require.ErrorAs(t, err, &tooMany)
+	a.Equal(groq.MaxImagesPerRequest+1, tooMany.Count)
+	a.Zero(calls)
+
+	_, err = client.ChatCompletion(ctx, groq.ChatCompletionRequest{
+		Model:    groq.ModelLlama3211BVisionPreview,
+		Messages: []groq.ChatCompletionMessage{groq.VisionMessage("hi", part)},
+	})
+	require.NoError(t, err)
+	a.Equal(1, calls)
+}